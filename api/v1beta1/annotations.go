@@ -0,0 +1,41 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+// Annotations that change how the operator treats a single resource. They
+// apply to JSMService and JSMTeam unless noted otherwise.
+const (
+	// ResyncIntervalAnnotation overrides the operator-wide --resync-interval
+	// for a single JSMService, e.g. "1h". "0" disables the periodic resync.
+	ResyncIntervalAnnotation = "jsm.macpaw.dev/resync-interval"
+
+	// SuspendAnnotation set to "true" pauses the reconciliation until it is
+	// removed, nothing is read from or written to JSM. Deleting a suspended
+	// resource leaves the remote object behind. The Suspended condition
+	// reports it.
+	SuspendAnnotation = "jsm.macpaw.dev/suspend"
+
+	// ReconcileAtAnnotation requests a full resync with JSM whenever its
+	// value changes, e.g. when set to the current time. The handled value is
+	// recorded in status.lastHandledReconcileAt.
+	ReconcileAtAnnotation = "jsm.macpaw.dev/reconcile-at"
+
+	// ReadOnlyAnnotation set to "true" makes the operator only observe the
+	// remote object and report drift on the Drifted condition, it never
+	// creates, changes or deletes it.
+	ReadOnlyAnnotation = "jsm.macpaw.dev/read-only"
+)
@@ -0,0 +1,97 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+// Standard condition types understood by kstatus, Argo CD and Flux. Every
+// reconcile sets all three, with the generation it acted upon.
+const (
+	// ConditionReady is True once the remote object matches the spec.
+	ConditionReady = "Ready"
+	// ConditionReconciling is True while the operator is still working
+	// towards the spec, e.g. waiting for a team or retrying a failed request.
+	ConditionReconciling = "Reconciling"
+	// ConditionStalled is True when the operator can't make progress until
+	// the spec or the remote object is fixed.
+	ConditionStalled = "Stalled"
+)
+
+// Domain condition types.
+const (
+	// ConditionTeamResolved tells whether the Opsgenie team ARI is known. It
+	// is reported on JSMTeam and, for the referenced team, on JSMService.
+	ConditionTeamResolved = "TeamResolved"
+	// ConditionTierResolved tells whether the service tier of the spec exists in JSM.
+	ConditionTierResolved = "TierResolved"
+	// ConditionRemoteSynced tells whether the JSM service matches the spec.
+	ConditionRemoteSynced = "RemoteSynced"
+	// ConditionRelationshipLinked tells whether the JSM service is linked with
+	// the referenced Opsgenie team.
+	ConditionRelationshipLinked = "RelationshipLinked"
+	// ConditionDeletionFailed is set while the JSM service can't be deleted.
+	ConditionDeletionFailed = "DeletionFailed"
+	// ConditionDrifted tells whether the last resync found the JSM service
+	// changed outside of the operator.
+	ConditionDrifted = "Drifted"
+	// ConditionSuspended is True while the suspend annotation stops the
+	// operator from reconciling the resource.
+	ConditionSuspended = "Suspended"
+)
+
+// Condition reasons.
+const (
+	ReasonReconciled  = "Reconciled"
+	ReasonProgressing = "Progressing"
+	ReasonDeleting    = "Deleting"
+	ReasonSuspended   = "Suspended"
+	ReasonReadOnly    = "ReadOnly"
+	ReasonDryRun      = "DryRun"
+
+	ReasonResolved          = "Resolved"
+	ReasonSpecifiedID       = "SpecifiedID"
+	ReasonNoTeamRef         = "NoTeamRef"
+	ReasonTeamNotFound      = "TeamNotFound"
+	ReasonTeamNotReady      = "TeamNotReady"
+	ReasonTeamRefNotAllowed = "TeamRefNotAllowed"
+
+	ReasonUnknownTier = "UnknownTier"
+	ReasonInvalidTier = "InvalidTier"
+
+	ReasonCreated         = "Created"
+	ReasonAdopted         = "Adopted"
+	ReasonAdoptionRefused = "AdoptionRefused"
+	ReasonRenamed         = "Renamed"
+	ReasonSynced          = "Synced"
+	ReasonLinked          = "Linked"
+
+	ReasonDeleteFailed = "DeleteFailed"
+
+	ReasonInSync         = "InSync"
+	ReasonDriftDetected  = "DriftDetected"
+	ReasonDriftCorrected = "DriftCorrected"
+	ReasonRemoteDeleted  = "RemoteDeleted"
+
+	// Reasons for failed JSM API requests.
+	ReasonRevisionConflict = "RevisionConflict"
+	ReasonNameConflict     = "NameConflict"
+	ReasonAmbiguousName    = "AmbiguousName"
+	ReasonInvalidSpec      = "InvalidSpec"
+	ReasonNotFound         = "NotFound"
+	ReasonAuthFailed       = "AuthFailed"
+	ReasonRateLimited      = "RateLimited"
+	ReasonServerError      = "ServerError"
+	ReasonAPIError         = "APIError"
+)
@@ -0,0 +1,44 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+// Event reasons. Events about the remote objects also use the condition
+// reasons Created, Adopted, AdoptionRefused, Renamed and DeleteFailed.
+const (
+	EventUpdated         = "Updated"
+	EventDeleted         = "Deleted"
+	EventTeamLinked      = "TeamLinked"
+	EventTeamUnlinked    = "TeamUnlinked"
+	EventConflictRetried = "ConflictRetried"
+	// A dry run planned changes instead of making them.
+	EventPlanned = "Planned"
+
+	// Reasons of warnings about failed JSM requests.
+	EventResolveFailed = "ResolveFailed"
+	EventCreateFailed  = "CreateFailed"
+	EventUpdateFailed  = "UpdateFailed"
+	EventLinkFailed    = "LinkFailed"
+)
+
+// Annotations of the events about JSM requests, so that tools don't have to
+// parse the message.
+const (
+	// EventAnnotationOperation is the JSM client operation, e.g. UpdateService.
+	EventAnnotationOperation = "jsm.macpaw.dev/operation"
+	// EventAnnotationARI is the ARI of the remote object, if it is known.
+	EventAnnotationARI = "jsm.macpaw.dev/ari"
+)
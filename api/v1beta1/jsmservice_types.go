@@ -31,22 +31,174 @@ type JSMServiceSpec struct {
 	// Optional service description
 	Description string `json:"description,omitempty"`
 
-	// Service tier level (1-4), required for creation
-	TierLevel int `json:"tierLevel"`
+	// Service tier level (1-4). Either tierLevel or tierName is required, if
+	// both are set they must refer to the same tier.
+	// +optional
+	TierLevel int `json:"tierLevel,omitempty"`
+
+	// Service tier name as shown in JSM (e.g., Critical)
+	// +optional
+	TierName string `json:"tierName,omitempty"`
 
 	// Optional: service type key (e.g., APPLICATIONS, BUSINESS_SERVICES)
 	ServiceTypeKey string `json:"serviceTypeKey,omitempty"`
 
-	// Reference to a JSMTeam for responders
+	// Reference to a JSMTeam for responders.
+	// Deprecated: use teamRefs. It is treated as an Owner in front of them.
+	// +optional
 	TeamRef *JSMTeamRef `json:"teamRef,omitempty"`
+
+	// The JSMTeams responding to the service. Owners are also linked with
+	// the service by an Opsgenie team relationship.
+	// +optional
+	TeamRefs []JSMTeamRef `json:"teamRefs,omitempty"`
+
+	// What happens to the JSM service when this resource is deleted.
+	// Defaults to the operator-wide --default-deletion-policy.
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// What happens when the JSM service was changed outside of the operator.
+	// Defaults to Correct.
+	// +optional
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
+
+	// Whether an existing JSM service with the same name may be taken over
+	// instead of creating one. Defaults to Never.
+	// +optional
+	AdoptionPolicy AdoptionPolicy `json:"adoptionPolicy,omitempty"`
+
+	// ARI of an existing JSM service to manage, instead of looking it up by
+	// name. It is adopted unless the adoption policy is set to Never.
+	// +optional
+	ExternalID string `json:"externalID,omitempty"`
+
+	// Plan the changes in JSM instead of making them. The operations that
+	// would be issued are written to status.plan and recorded as an event.
+	// The operator-wide --dry-run applies to every service.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// DeletionPolicy decides what happens to a JSM service when the resource
+// managing it is deleted.
+// +kubebuilder:validation:Enum=Delete;Orphan
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete deletes the JSM service together with the resource.
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyOrphan leaves the JSM service behind.
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+)
+
+// DriftPolicy decides what happens when a periodic resync finds that the JSM
+// service no longer matches the spec.
+// +kubebuilder:validation:Enum=Correct;Report
+type DriftPolicy string
+
+const (
+	// DriftPolicyCorrect reapplies the spec.
+	DriftPolicyCorrect DriftPolicy = "Correct"
+	// DriftPolicyReport only reports the drift on the Drifted condition.
+	DriftPolicyReport DriftPolicy = "Report"
+)
+
+// AdoptionPolicy decides whether an existing JSM service is taken over.
+// +kubebuilder:validation:Enum=Never;IfUnowned;Always
+type AdoptionPolicy string
+
+const (
+	// AdoptionPolicyNever never takes over an existing service.
+	AdoptionPolicyNever AdoptionPolicy = "Never"
+	// AdoptionPolicyIfUnowned takes over an existing service only if no team
+	// other than the referenced ones responds to it or is linked with it.
+	// JSM records no owner of a service, so this is a best-effort check: a
+	// service another cluster manages for the same teams is taken over.
+	AdoptionPolicyIfUnowned AdoptionPolicy = "IfUnowned"
+	// AdoptionPolicyAlways takes over an existing service with the same name.
+	AdoptionPolicyAlways AdoptionPolicy = "Always"
+)
+
+// AdoptionSource tells how an adopted JSM service was found.
+type AdoptionSource string
+
+const (
+	// AdoptionSourceName means the service was found by its name.
+	AdoptionSourceName AdoptionSource = "Name"
+	// AdoptionSourceExternalID means the service was pinned by spec.externalID.
+	AdoptionSourceExternalID AdoptionSource = "ExternalID"
+	// AdoptionSourcePendingCreate means the service was found by the name an
+	// interrupted create recorded in status.pendingCreate.
+	AdoptionSourcePendingCreate AdoptionSource = "PendingCreate"
+)
+
+// AdoptionStatus records how an existing JSM service was taken over.
+type AdoptionStatus struct {
+	// When the service was adopted
+	Time metav1.Time `json:"time"`
+	// How the service was found
+	Source AdoptionSource `json:"source"`
+	// The adoption policy in effect
+	Policy AdoptionPolicy `json:"policy"`
+}
+
+// PendingCreate is recorded before a JSM service or a managed Opsgenie team is
+// created. A reconcile that died before it stored the ID of the new object
+// finds it by the recorded name instead of creating a second one. JSM keeps nothing that identifies the
+// attempt, so the name is all the recovery goes by.
+type PendingCreate struct {
+	// Name the service is created with
+	Name string `json:"name"`
+	// When the first attempt started
+	Time metav1.Time `json:"time"`
 }
 
 // JSMTeamRef allows referencing a JSMTeam object
 type JSMTeamRef struct {
 	// Name of the JSMTeam resource
 	Name string `json:"name"`
+
+	// Namespace of the JSMTeam resource, defaults to the namespace of the
+	// service. A team in another namespace must allow the namespace of the
+	// service in spec.allowedNamespaces.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// What the team is to the service. Defaults to Owner.
+	// +optional
+	Role TeamRole `json:"role,omitempty"`
+}
+
+// TeamRole tells what a referenced team is to a JSM service.
+// +kubebuilder:validation:Enum=Owner;Responder
+type TeamRole string
+
+const (
+	// TeamRoleOwner teams respond to the service and are linked with it by
+	// an Opsgenie team relationship.
+	TeamRoleOwner TeamRole = "Owner"
+	// TeamRoleResponder teams only respond to the service.
+	TeamRoleResponder TeamRole = "Responder"
+)
+
+// ServiceTeamStatus is a referenced team as resolved for the JSM service.
+type ServiceTeamStatus struct {
+	// Name and namespace of the JSMTeam resource
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+
+	Role TeamRole `json:"role"`
+	// ARI of the Opsgenie team
+	ID string `json:"id"`
+	// ID of the Opsgenie team relationship, only set for owners
+	RelationshipID string `json:"relationshipID,omitempty"`
 }
 
+// ServiceFinalizer is added to every JSMService so the JSM service can be
+// cleaned up before the resource goes away.
+const ServiceFinalizer = "jsm.macpaw.dev/finalizer"
+
 // JSMServiceStatus defines the observed state of JSMService.
 type JSMServiceStatus struct {
 	// Standard Kubernetes status conditions
@@ -58,8 +210,37 @@ type JSMServiceStatus struct {
 	ObservedGeneration int64  `json:"observedGeneration,omitempty"`
 	TierID             string `json:"tierID,omitempty"`
 	TierLevel          int    `json:"tierLevel,omitempty"`
+	TierName           string `json:"tierName,omitempty"`
+	// Relationship ID and ARI of the first owner team.
+	// Deprecated: use teams.
 	TeamRelationshipID string `json:"teamRelationshipID,omitempty"`
 	ResolvedTeamARN    string `json:"resolvedTeamARN,omitempty"`
+
+	// The referenced teams as last applied to the JSM service.
+	Teams []ServiceTeamStatus `json:"teams,omitempty"`
+
+	// When the JSM service was last written or compared with the spec.
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	// The fields that differed from the spec at the last resync, e.g.
+	// description or tier.
+	DriftedFields []string `json:"driftedFields,omitempty"`
+
+	// Name of the JSM service as last applied. The ID, not the name,
+	// identifies the service, so changing spec.name renames it in place.
+	Name string `json:"name,omitempty"`
+
+	// Set if the JSM service existed before and was adopted.
+	Adoption *AdoptionStatus `json:"adoption,omitempty"`
+
+	// Set while a JSM service is being created, until its ID is stored.
+	PendingCreate *PendingCreate `json:"pendingCreate,omitempty"`
+
+	// Value of the reconcile-at annotation the last forced resync was done for.
+	LastHandledReconcileAt string `json:"lastHandledReconcileAt,omitempty"`
+
+	// The operations a dry run would issue in JSM, in order. Empty once
+	// the JSM service matches the spec or outside of dry runs.
+	Plan []string `json:"plan,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -30,13 +30,104 @@ type JSMTeamSpec struct {
 
 	// Optional: ARI of the team if known
 	ID string `json:"id,omitempty"`
+
+	// Namespaces whose JSMServices may reference this team, "*" allows all.
+	// Services in the namespace of the team can always reference it.
+	// +optional
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+
+	// Whether the operator only looks the Opsgenie team up or owns it.
+	// Defaults to Lookup.
+	// +kubebuilder:default=Lookup
+	// +optional
+	ManagementPolicy ManagementPolicy `json:"managementPolicy,omitempty"`
+
+	// Description of the Opsgenie team, only applied by the Manage policy.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Members of the Opsgenie team, only applied by the Manage policy.
+	// Members that aren't listed are removed from the team. Without members
+	// the members of the team are left alone.
+	// +listType=map
+	// +listMapKey=username
+	// +optional
+	Members []TeamMember `json:"members,omitempty"`
+
+	// What happens to a managed Opsgenie team when this resource is deleted.
+	// Defaults to Orphan, a team that is only looked up is never deleted.
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// ManagementPolicy decides how much of an Opsgenie team the operator owns.
+// +kubebuilder:validation:Enum=Lookup;Manage
+type ManagementPolicy string
+
+const (
+	// ManagementPolicyLookup only resolves the ARI of an existing team.
+	ManagementPolicyLookup ManagementPolicy = "Lookup"
+	// ManagementPolicyManage creates the team if it is missing and keeps its
+	// name, description and, if listed, members in line with the spec.
+	ManagementPolicyManage ManagementPolicy = "Manage"
+)
+
+// TeamMember is a user in an Opsgenie team.
+type TeamMember struct {
+	// Username, i.e. the email address, of the Atlassian account.
+	// +kubebuilder:validation:MinLength=1
+	Username string `json:"username"`
+
+	// Role of the user in the team, admins can manage the team. Defaults to
+	// User.
+	// +kubebuilder:default=User
+	// +optional
+	Role TeamMemberRole `json:"role,omitempty"`
 }
 
+// TeamMemberRole is the role of a user in an Opsgenie team.
+// +kubebuilder:validation:Enum=Admin;User
+type TeamMemberRole string
+
+const (
+	// TeamMemberRoleAdmin members can manage the team.
+	TeamMemberRoleAdmin TeamMemberRole = "Admin"
+	// TeamMemberRoleUser is the role of regular members.
+	TeamMemberRoleUser TeamMemberRole = "User"
+)
+
+// TeamFinalizer is added to managed JSMTeams so the Opsgenie team can be
+// deleted before the resource goes away.
+const TeamFinalizer = "jsm.macpaw.dev/finalizer"
+
 // JSMTeamStatus defines the observed state of JSMTeam.
 type JSMTeamStatus struct {
+	// Standard Kubernetes status conditions
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
 	// The resolved or confirmed team ARI
 	ID                 string `json:"id,omitempty"`
 	ObservedGeneration int64  `json:"observedGeneration,omitempty"`
+
+	// Value of the reconcile-at annotation the last forced resync was done for.
+	LastHandledReconcileAt string `json:"lastHandledReconcileAt,omitempty"`
+
+	// ARI of the Opsgenie team the operator created for a managed team. Only
+	// this team or the one pinned by spec.id is managed, an existing team
+	// with the same name is never taken over.
+	// +optional
+	CreatedID string `json:"createdID,omitempty"`
+
+	// The create of a managed team in progress, recorded before the Opsgenie
+	// team is created. While it is set, a team with the recorded name is
+	// taken as the one the operator created rather than refused.
+	// +optional
+	PendingCreate *PendingCreate `json:"pendingCreate,omitempty"`
+
+	// The operations a dry run would issue for a managed team, in order.
+	// Empty once the Opsgenie team matches the spec or outside of dry runs.
+	// +optional
+	Plan []string `json:"plan,omitempty"`
 }
 
 // +kubebuilder:object:root=true
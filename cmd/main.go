@@ -22,6 +22,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -66,10 +68,24 @@ func main() {
 	var secureMetrics bool
 	var enableHTTP2 bool
 	var jsmApiToken string
+	var jsmAuthMode string
+	var jsmOAuth2 client.OAuth2Config
+	var jsmOAuth2Scopes string
 	var jsmUsername string
 	var jsmGraphQLURL string
 	var jsmOpsRestURL string
 	var jsmCloudID string
+	var jsmCaseInsensitiveNames bool
+	var jsmTeamCacheTTL time.Duration
+	var jsmTierCacheTTL time.Duration
+	var jsmRateLimit client.RateLimitConfig
+	var defaultDeletionPolicy string
+	var resyncInterval time.Duration
+	var dryRun bool
+	var serviceQueue, teamQueue controller.QueueConfig
+	var queueQPS float64
+	var queueBurst int
+	var jsmHTTP client.HTTPConfig
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -103,6 +119,76 @@ func main() {
 	flag.StringVar(&jsmCloudID, "jsm-cloud-id", "", "The Atlassian Cloud ID. ")
 	flag.StringVar(&jsmOpsRestURL, "jsm-rest-url", defaultJSMOpsRestURL, "The JSM REST API URL. ")
 	flag.StringVar(&jsmUsername, "jsm-username", "", "The JSM username. This is used for authentication with the JSM API. ")
+	flag.StringVar(&jsmAuthMode, "jsm-auth-mode", envOrDefault("JSM_AUTH_MODE", string(client.AuthModeBasic)),
+		"How to authenticate with the JSM API: basic (username + API token), bearer (scoped API token) "+
+			"or oauth2 (client credentials).")
+	flag.StringVar(&jsmOAuth2.ClientID, "jsm-oauth-client-id", os.Getenv("JSM_OAUTH_CLIENT_ID"),
+		"The OAuth 2.0 client ID, used with --jsm-auth-mode=oauth2.")
+	flag.StringVar(&jsmOAuth2.ClientSecret, "jsm-oauth-client-secret", os.Getenv("JSM_OAUTH_CLIENT_SECRET"),
+		"The OAuth 2.0 client secret, used with --jsm-auth-mode=oauth2.")
+	flag.StringVar(&jsmOAuth2.TokenURL, "jsm-oauth-token-url",
+		envOrDefault("JSM_OAUTH_TOKEN_URL", client.DefaultOAuth2TokenURL), "The OAuth 2.0 token endpoint.")
+	flag.StringVar(&jsmOAuth2.Audience, "jsm-oauth-audience",
+		envOrDefault("JSM_OAUTH_AUDIENCE", client.DefaultOAuth2Audience), "The audience OAuth 2.0 tokens are requested for.")
+	flag.StringVar(&jsmOAuth2Scopes, "jsm-oauth-scopes", os.Getenv("JSM_OAUTH_SCOPES"),
+		"Comma separated OAuth 2.0 scopes to request.")
+	flag.BoolVar(&jsmCaseInsensitiveNames, "jsm-case-insensitive-names", false,
+		"If set, JSM services and teams are matched by name ignoring letter case.")
+	flag.DurationVar(&jsmTeamCacheTTL, "jsm-team-cache-ttl", client.DefaultTeamCacheTTL,
+		"How long the Opsgenie team directory is cached before it is fetched again.")
+	flag.DurationVar(&jsmTierCacheTTL, "jsm-tier-cache-ttl", client.DefaultTierCacheTTL,
+		"How long the JSM service tier catalog is cached before it is fetched again.")
+	flag.Float64Var(&jsmRateLimit.QPS, "jsm-rate-limit-qps", client.DefaultRateLimitQPS,
+		"Sustained number of requests per second the operator sends to the JSM APIs. Use 0 to disable the limit.")
+	flag.IntVar(&jsmRateLimit.Burst, "jsm-rate-limit-burst", client.DefaultRateLimitBurst,
+		"Number of requests that may be sent to the JSM APIs at once.")
+	flag.IntVar(&jsmRateLimit.MaxRetries, "jsm-max-retries", client.DefaultMaxRetries,
+		"How many times a throttled or failed JSM query is retried. Mutations are never retried.")
+	flag.DurationVar(&jsmRateLimit.MaxRetryWait, "jsm-max-retry-wait", client.DefaultMaxRetryWait,
+		"The longest delay to wait for before retrying a JSM query. Longer delays requeue the reconcile instead.")
+	flag.DurationVar(&jsmHTTP.RequestTimeout, "jsm-request-timeout", client.DefaultRequestTimeout,
+		"Timeout of a single JSM request attempt, including reading the response.")
+	flag.StringVar(&jsmHTTP.ProxyURL, "jsm-proxy-url", os.Getenv("JSM_PROXY_URL"),
+		"HTTP(S) proxy for the JSM APIs. When empty HTTPS_PROXY, HTTP_PROXY and NO_PROXY apply.")
+	flag.StringVar(&jsmHTTP.CAFile, "jsm-ca-file", "",
+		"PEM file with CA certificates trusted in addition to the system ones, e.g. of a TLS intercepting proxy. "+
+			"The PEM data can also be passed in the JSM_CA_BUNDLE environment variable, e.g. from a Secret.")
+	flag.StringVar(&jsmHTTP.CertFile, "jsm-client-cert-file", "",
+		"PEM client certificate for mutual TLS. The PEM data can also be passed in JSM_CLIENT_CERT.")
+	flag.StringVar(&jsmHTTP.KeyFile, "jsm-client-key-file", "",
+		"PEM client key for mutual TLS. The PEM data can also be passed in JSM_CLIENT_KEY.")
+	flag.IntVar(&jsmHTTP.MaxIdleConnsPerHost, "jsm-max-idle-conns-per-host", client.DefaultMaxIdleConnsPerHost,
+		"Number of idle connections kept open to the JSM APIs.")
+	flag.IntVar(&jsmHTTP.MaxConnsPerHost, "jsm-max-conns-per-host", 0,
+		"Maximum number of connections to the JSM APIs, 0 means no limit.")
+	jsmHTTP.CAData = []byte(os.Getenv("JSM_CA_BUNDLE"))
+	jsmHTTP.CertData = []byte(os.Getenv("JSM_CLIENT_CERT"))
+	jsmHTTP.KeyData = []byte(os.Getenv("JSM_CLIENT_KEY"))
+	flag.StringVar(&defaultDeletionPolicy, "default-deletion-policy", string(jsmv1beta1.DeletionPolicyOrphan),
+		"What happens to a JSM service when its JSMService is deleted and it sets no deletionPolicy: Delete or Orphan.")
+	flag.DurationVar(&resyncInterval, "resync-interval", 10*time.Minute,
+		"How often reconciled JSM services are compared with their spec to detect drift, 0 disables it. "+
+			"The "+jsmv1beta1.ResyncIntervalAnnotation+" annotation overrides it per JSMService.")
+	flag.BoolVar(&dryRun, "dry-run", false,
+		"Plan the changes in JSM instead of making them, as if every JSMService set spec.dryRun, "+
+			"and only plan the changes of managed JSMTeams. "+
+			"JSM is still queried so the plans are accurate.")
+	flag.IntVar(&serviceQueue.MaxConcurrentReconciles, "service-max-concurrent-reconciles", 1,
+		"Number of JSMServices reconciled at once.")
+	flag.DurationVar(&serviceQueue.BaseBackoff, "service-backoff-base", controller.DefaultServiceBaseBackoff,
+		"Delay before a JSMService whose reconcile failed is retried, doubled on every further failure.")
+	flag.DurationVar(&serviceQueue.MaxBackoff, "service-backoff-max", controller.DefaultServiceMaxBackoff,
+		"Longest delay before a failed JSMService is retried.")
+	flag.IntVar(&teamQueue.MaxConcurrentReconciles, "team-max-concurrent-reconciles", 1,
+		"Number of JSMTeams reconciled at once.")
+	flag.DurationVar(&teamQueue.BaseBackoff, "team-backoff-base", controller.DefaultTeamBaseBackoff,
+		"Delay before a JSMTeam whose reconcile failed is retried, doubled on every further failure.")
+	flag.DurationVar(&teamQueue.MaxBackoff, "team-backoff-max", controller.DefaultTeamMaxBackoff,
+		"Longest delay before a failed JSMTeam is retried.")
+	flag.Float64Var(&queueQPS, "queue-qps", controller.DefaultQueueQPS,
+		"Overall number of JSMServices and JSMTeams reconciled per second, 0 disables the limit.")
+	flag.IntVar(&queueBurst, "queue-burst", controller.DefaultQueueBurst,
+		"Number of JSMServices and JSMTeams that may be reconciled at once above --queue-qps.")
 
 	if jsmApiToken == "" {
 		jsmApiToken = os.Getenv("JSM_API_TOKEN")
@@ -121,8 +207,24 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
-	if jsmApiToken == "" {
-		setupLog.Error(nil, "JSM API token is required")
+	switch client.AuthMode(jsmAuthMode) {
+	case client.AuthModeBasic, client.AuthModeBearer:
+		if jsmApiToken == "" {
+			setupLog.Error(nil, "JSM API token is required")
+			os.Exit(1)
+		}
+	case client.AuthModeOAuth2:
+		if jsmOAuth2.ClientID == "" || jsmOAuth2.ClientSecret == "" {
+			setupLog.Error(nil, "JSM OAuth 2.0 client ID and client secret are required")
+			os.Exit(1)
+		}
+		for _, scope := range strings.Split(jsmOAuth2Scopes, ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				jsmOAuth2.Scopes = append(jsmOAuth2.Scopes, scope)
+			}
+		}
+	default:
+		setupLog.Error(nil, "Invalid JSM auth mode, must be basic, bearer or oauth2", "mode", jsmAuthMode)
 		os.Exit(1)
 	}
 
@@ -141,16 +243,51 @@ func main() {
 		os.Exit(1)
 	}
 
+	switch jsmv1beta1.DeletionPolicy(defaultDeletionPolicy) {
+	case jsmv1beta1.DeletionPolicyDelete, jsmv1beta1.DeletionPolicyOrphan:
+	default:
+		setupLog.Error(nil, "Invalid default deletion policy, must be Delete or Orphan", "policy", defaultDeletionPolicy)
+		os.Exit(1)
+	}
+
+	for name, queue := range map[string]controller.QueueConfig{"service": serviceQueue, "team": teamQueue} {
+		if queue.MaxConcurrentReconciles < 1 || queue.BaseBackoff <= 0 || queue.MaxBackoff < queue.BaseBackoff {
+			setupLog.Error(nil, "Invalid queue settings, need at least one worker and a positive backoff base no longer than its max",
+				"controller", name, "maxConcurrentReconciles", queue.MaxConcurrentReconciles,
+				"backoffBase", queue.BaseBackoff, "backoffMax", queue.MaxBackoff)
+			os.Exit(1)
+		}
+	}
+	if queueQPS > 0 && queueBurst < 1 {
+		setupLog.Error(nil, "Invalid queue burst, must be at least 1 unless --queue-qps is 0", "burst", queueBurst)
+		os.Exit(1)
+	}
+	// the limit applies to both controllers together
+	queueLimiter := controller.NewQueueLimiter(queueQPS, queueBurst)
+	serviceQueue.Limiter, teamQueue.Limiter = queueLimiter, queueLimiter
+
 	jsmOpsRestURL = fmt.Sprintf("%s/%s", jsmOpsRestURL, jsmCloudID)
 
 	// Create a new JSM client with the provided configuration
 	jsmClient, err := client.NewJSMClient(client.JSMConfig{
 		GraphQLURL: jsmGraphQLURL,
 		RestURL:    jsmOpsRestURL,
+		AuthMode:   client.AuthMode(jsmAuthMode),
 		Token:      jsmApiToken,
 		Username:   jsmUsername,
+		OAuth2:     jsmOAuth2,
 		CloudID:    jsmCloudID,
+
+		CaseInsensitiveNames: jsmCaseInsensitiveNames,
+		TeamCacheTTL:         jsmTeamCacheTTL,
+		TierCacheTTL:         jsmTierCacheTTL,
+		RateLimit:            jsmRateLimit,
+		HTTP:                 jsmHTTP,
 	})
+	if err != nil {
+		setupLog.Error(err, "unable to create JSM client")
+		os.Exit(1)
+	}
 
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
@@ -266,9 +403,14 @@ func main() {
 	}
 
 	if err = (&controller.JSMServiceReconciler{
-		Client:    mgr.GetClient(),
-		Scheme:    mgr.GetScheme(),
-		JSMClient: jsmClient,
+		Client:                mgr.GetClient(),
+		Scheme:                mgr.GetScheme(),
+		JSMClient:             jsmClient,
+		DefaultDeletionPolicy: jsmv1beta1.DeletionPolicy(defaultDeletionPolicy),
+		ResyncInterval:        resyncInterval,
+		DryRun:                dryRun,
+		Queue:                 serviceQueue,
+		Recorder:              mgr.GetEventRecorderFor("jsmservice-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "JSMService")
 		os.Exit(1)
@@ -277,6 +419,9 @@ func main() {
 		Client:    mgr.GetClient(),
 		Scheme:    mgr.GetScheme(),
 		JSMClient: jsmClient,
+		Recorder:  mgr.GetEventRecorderFor("jsmteam-controller"),
+		DryRun:    dryRun,
+		Queue:     teamQueue,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "JSMTeam")
 		os.Exit(1)
@@ -314,3 +459,12 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// envOrDefault returns the value of the environment variable, or def if it is
+// not set.
+func envOrDefault(key, def string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return def
+}
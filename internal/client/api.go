@@ -0,0 +1,51 @@
+package client
+
+import "context"
+
+// API is the set of JSM operations the reconcilers depend on. *JSMClient
+// implements it against Atlassian, tests can point a JSMClient at the fake
+// backend in the fake package or provide their own implementation.
+type API interface {
+	// GetServiceByName returns the service with exactly the given name, or nil.
+	GetServiceByName(ctx context.Context, name string) (*Service, error)
+	// GetServiceByID returns the full state of the service with the given ARI.
+	GetServiceByID(ctx context.Context, id string) (*Service, error)
+	// CreateService creates a new service.
+	CreateService(ctx context.Context, req *CreateServiceRequest) (*Service, error)
+	// UpdateService updates an existing service, guarded by its revision.
+	UpdateService(ctx context.Context, req *UpdateServiceRequest) (*Service, error)
+	// DeleteService deletes a service.
+	DeleteService(ctx context.Context, id string) error
+	// GetTierIDByLevel resolves the ID of a service tier.
+	GetTierIDByLevel(ctx context.Context, level int) (string, error)
+	// GetTierByLevel returns the service tier with the given level.
+	GetTierByLevel(ctx context.Context, level int) (*Tier, error)
+	// GetTierByName returns the service tier with the given name.
+	GetTierByName(ctx context.Context, name string) (*Tier, error)
+	// ListServiceTiers returns every service tier.
+	ListServiceTiers(ctx context.Context) ([]Tier, error)
+	// InvalidateTierCache drops any cached tier catalog.
+	InvalidateTierCache()
+	// CreateOpsgenieTeamRelationship links a service with an Opsgenie team.
+	CreateOpsgenieTeamRelationship(ctx context.Context, serviceID, teamID string) (string, error)
+	// ListOpsgenieTeamRelationships returns every team relationship of a service.
+	ListOpsgenieTeamRelationships(ctx context.Context, serviceID string) ([]TeamRelationship, error)
+	// DeleteOpsgenieTeamRelationship unlinks a service from an Opsgenie team.
+	DeleteOpsgenieTeamRelationship(ctx context.Context, relationshipID string) error
+	// GetOpsgenieTeamIDByName resolves the ARI of an Opsgenie team.
+	GetOpsgenieTeamIDByName(ctx context.Context, name string) (string, error)
+	// ListOpsgenieTeams returns every Opsgenie team.
+	ListOpsgenieTeams(ctx context.Context) ([]Team, error)
+	// InvalidateTeamCache drops any cached team directory.
+	InvalidateTeamCache()
+	// GetOpsgenieTeam returns an Opsgenie team with its members.
+	GetOpsgenieTeam(ctx context.Context, id string) (*OpsgenieTeam, error)
+	// CreateOpsgenieTeam creates an Opsgenie team.
+	CreateOpsgenieTeam(ctx context.Context, req *OpsgenieTeamRequest) (*OpsgenieTeam, error)
+	// UpdateOpsgenieTeam replaces the name, description and members of an Opsgenie team.
+	UpdateOpsgenieTeam(ctx context.Context, id string, req *OpsgenieTeamRequest) error
+	// DeleteOpsgenieTeam deletes an Opsgenie team.
+	DeleteOpsgenieTeam(ctx context.Context, id string) error
+}
+
+var _ API = (*JSMClient)(nil)
@@ -0,0 +1,126 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// AuthMode selects how the operator authenticates with Atlassian.
+type AuthMode string
+
+const (
+	// AuthModeBasic sends the username and the API token as basic auth.
+	AuthModeBasic AuthMode = "basic"
+	// AuthModeBearer sends the token as a bearer token, e.g. a scoped API
+	// token of a service account.
+	AuthModeBearer AuthMode = "bearer"
+	// AuthModeOAuth2 obtains bearer tokens with the OAuth 2.0 client
+	// credentials grant and refreshes them before they expire.
+	AuthModeOAuth2 AuthMode = "oauth2"
+)
+
+// Defaults for OAuth2Config.
+const (
+	DefaultOAuth2TokenURL = "https://auth.atlassian.com/oauth/token"
+	DefaultOAuth2Audience = "api.atlassian.com"
+)
+
+// OAuth2Config configures the OAuth 2.0 client credentials grant.
+type OAuth2Config struct {
+	ClientID     string
+	ClientSecret string
+	// TokenURL defaults to DefaultOAuth2TokenURL.
+	TokenURL string
+	// Audience defaults to DefaultOAuth2Audience.
+	Audience string
+	Scopes   []string
+}
+
+// validateAuth checks that the credentials of the selected mode are set.
+func validateAuth(config JSMConfig) error {
+	switch config.AuthMode {
+	case "", AuthModeBasic:
+		if config.Username == "" || config.Token == "" {
+			return errors.New("invalid JSM configuration: basic auth needs a username and a token")
+		}
+	case AuthModeBearer:
+		if config.Token == "" {
+			return errors.New("invalid JSM configuration: bearer auth needs a token")
+		}
+	case AuthModeOAuth2:
+		if config.OAuth2.ClientID == "" || config.OAuth2.ClientSecret == "" {
+			return errors.New("invalid JSM configuration: oauth2 auth needs a client ID and a client secret")
+		}
+	default:
+		return fmt.Errorf("invalid JSM configuration: unknown auth mode %q", config.AuthMode)
+	}
+	return nil
+}
+
+// newAuthTransport wraps base with the credentials of the selected mode.
+// tokenTransport is used to talk to the OAuth 2.0 token endpoint, it must not
+// carry any credentials itself.
+func newAuthTransport(config JSMConfig, base, tokenTransport http.RoundTripper) http.RoundTripper {
+	switch config.AuthMode {
+	case AuthModeBearer:
+		return &headerAuthTransport{base: base, header: "Bearer " + config.Token}
+	case AuthModeOAuth2:
+		cc := clientcredentials.Config{
+			ClientID:       config.OAuth2.ClientID,
+			ClientSecret:   config.OAuth2.ClientSecret,
+			TokenURL:       config.OAuth2.TokenURL,
+			Scopes:         config.OAuth2.Scopes,
+			EndpointParams: url.Values{"audience": {config.OAuth2.Audience}},
+			AuthStyle:      oauth2.AuthStyleInParams,
+		}
+		if cc.TokenURL == "" {
+			cc.TokenURL = DefaultOAuth2TokenURL
+		}
+		if config.OAuth2.Audience == "" {
+			cc.EndpointParams.Set("audience", DefaultOAuth2Audience)
+		}
+		// the token source lives as long as the client, so it must not be
+		// bound to the context of a single request
+		ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Transport: tokenTransport})
+		return &oauth2.Transport{Source: &typedTokenSource{base: cc.TokenSource(ctx)}, Base: base}
+	default:
+		return &headerAuthTransport{base: base, header: "Basic " + basicAuth(config.Username, config.Token)}
+	}
+}
+
+// headerAuthTransport sets a static Authorization header on every request.
+type headerAuthTransport struct {
+	base   http.RoundTripper
+	header string
+}
+
+func (t *headerAuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// a RoundTripper must not modify the request it was given
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", t.header)
+	return t.base.RoundTrip(req)
+}
+
+// typedTokenSource reports a rejected token request as an *AuthError, so it
+// is classified like any other authentication failure.
+type typedTokenSource struct {
+	base oauth2.TokenSource
+}
+
+func (s *typedTokenSource) Token() (*oauth2.Token, error) {
+	token, err := s.base.Token()
+	var retrieve *oauth2.RetrieveError
+	if errors.As(err, &retrieve) && retrieve.Response != nil && retrieve.Response.StatusCode < http.StatusInternalServerError {
+		return nil, &AuthError{
+			StatusCode: http.StatusUnauthorized,
+			Message:    fmt.Sprintf("oauth2 token request failed: %s", retrieve.Error()),
+		}
+	}
+	return token, err
+}
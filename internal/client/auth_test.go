@@ -0,0 +1,161 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Authentication", func() {
+	var (
+		api     *httptest.Server
+		mu      sync.Mutex
+		headers []string
+	)
+
+	BeforeEach(func() {
+		headers = nil
+		api = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			headers = append(headers, r.Header.Get("Authorization"))
+			mu.Unlock()
+			servicePages([][]string{{}})(w, r)
+		}))
+	})
+
+	AfterEach(func() {
+		api.Close()
+	})
+
+	newClient := func(config JSMConfig) *JSMClient {
+		config.GraphQLURL = api.URL
+		config.RestURL = api.URL
+		config.CloudID = "cloud"
+		c, err := NewJSMClient(config)
+		Expect(err).NotTo(HaveOccurred())
+		return c
+	}
+
+	lookup := func(c *JSMClient) error {
+		_, err := c.GetServiceByName(context.Background(), "api")
+		return err
+	}
+
+	It("sends basic auth by default", func() {
+		c := newClient(JSMConfig{Username: "user", Token: "token"})
+		Expect(lookup(c)).To(Succeed())
+		Expect(headers).To(ConsistOf("Basic " + basicAuth("user", "token")))
+	})
+
+	It("sends a bearer token", func() {
+		c := newClient(JSMConfig{AuthMode: AuthModeBearer, Token: "scoped-token"})
+		Expect(lookup(c)).To(Succeed())
+		Expect(headers).To(ConsistOf("Bearer scoped-token"))
+	})
+
+	It("validates the credentials of the selected mode", func() {
+		for _, config := range []JSMConfig{
+			{Token: "token"},
+			{AuthMode: AuthModeBearer},
+			{AuthMode: AuthModeOAuth2, OAuth2: OAuth2Config{ClientID: "id"}},
+			{AuthMode: "kerberos", Token: "token"},
+		} {
+			config.GraphQLURL, config.RestURL, config.CloudID = api.URL, api.URL, "cloud"
+			_, err := NewJSMClient(config)
+			Expect(err).To(HaveOccurred(), "mode %q", config.AuthMode)
+		}
+	})
+
+	Context("with OAuth 2.0 client credentials", func() {
+		var (
+			tokenServer *httptest.Server
+			issued      atomic.Int32
+			expiresIn   int
+			tokenStatus int
+		)
+
+		BeforeEach(func() {
+			issued.Store(0)
+			expiresIn = 3600
+			tokenStatus = http.StatusOK
+			tokenServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.ParseForm()).To(Succeed())
+				Expect(r.Form.Get("grant_type")).To(Equal("client_credentials"))
+				Expect(r.Form.Get("client_id")).To(Equal("id"))
+				Expect(r.Form.Get("client_secret")).To(Equal("secret"))
+				Expect(r.Form.Get("audience")).To(Equal(DefaultOAuth2Audience))
+				if tokenStatus != http.StatusOK {
+					http.Error(w, `{"error":"access_denied"}`, tokenStatus)
+					return
+				}
+				n := issued.Add(1)
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(map[string]any{
+					"access_token": fmt.Sprintf("token-%d", n),
+					"token_type":   "Bearer",
+					"expires_in":   expiresIn,
+				})
+			}))
+		})
+
+		AfterEach(func() {
+			tokenServer.Close()
+		})
+
+		oauthClient := func() *JSMClient {
+			return newClient(JSMConfig{
+				AuthMode: AuthModeOAuth2,
+				OAuth2:   OAuth2Config{ClientID: "id", ClientSecret: "secret", TokenURL: tokenServer.URL},
+			})
+		}
+
+		It("reuses a token until it expires", func() {
+			c := oauthClient()
+			Expect(lookup(c)).To(Succeed())
+			Expect(lookup(c)).To(Succeed())
+			Expect(issued.Load()).To(BeEquivalentTo(1))
+			Expect(headers).To(ConsistOf("Bearer token-1", "Bearer token-1"))
+		})
+
+		It("refreshes an expired token", func() {
+			// tokens this short lived are already considered expired
+			expiresIn = 1
+			c := oauthClient()
+			Expect(lookup(c)).To(Succeed())
+			Expect(lookup(c)).To(Succeed())
+			Expect(headers).To(ConsistOf("Bearer token-1", "Bearer token-2"))
+		})
+
+		It("reports a rejected token request as an auth error", func() {
+			tokenStatus = http.StatusUnauthorized
+			err := lookup(oauthClient())
+			var authErr *AuthError
+			Expect(errors.As(err, &authErr)).To(BeTrue(), "got %v", err)
+			Expect(headers).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,310 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hasura/go-graphql-client"
+)
+
+// revisionConflictMessage is the message JSM returns when an update carries a
+// stale revision. Some responses only carry the message, so it is used as a
+// fallback when classifying errors.
+const revisionConflictMessage = "Specified revision was incorrect"
+
+// AmbiguousNameError is returned when a lookup by name matches more than one
+// remote object, so the operator can't safely pick one of them.
+type AmbiguousNameError struct {
+	// Kind is the kind of remote object, e.g. "service".
+	Kind string
+	Name string
+	// IDs are the ARIs of all the objects that matched.
+	IDs []string
+}
+
+func (e *AmbiguousNameError) Error() string {
+	return fmt.Sprintf("%s name %q is ambiguous: %d matches (%s)", e.Kind, e.Name, len(e.IDs), strings.Join(e.IDs, ", "))
+}
+
+// RevisionConflictError is returned when a mutation carried a stale revision.
+// The caller should refresh the remote object and retry.
+type RevisionConflictError struct {
+	Message string
+}
+
+func (e *RevisionConflictError) Error() string {
+	return "revision conflict: " + e.Message
+}
+
+// NotFoundError is returned when the remote object does not exist.
+type NotFoundError struct {
+	Message string
+}
+
+func (e *NotFoundError) Error() string {
+	return "not found: " + e.Message
+}
+
+// AlreadyExistsError is returned when the object being created already exists.
+type AlreadyExistsError struct {
+	Message string
+}
+
+func (e *AlreadyExistsError) Error() string {
+	return "already exists: " + e.Message
+}
+
+// FieldError describes a single invalid input field.
+type FieldError struct {
+	// Path is the dotted path of the field, e.g. "input.serviceTier".
+	Path    string
+	Message string
+}
+
+// ValidationError is returned when JSM rejected the request input. Retrying
+// the same request won't help.
+type ValidationError struct {
+	Message string
+	Fields  []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Fields) == 0 {
+		return "validation failed: " + e.Message
+	}
+	fields := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		fields = append(fields, fmt.Sprintf("%s: %s", f.Path, f.Message))
+	}
+	return fmt.Sprintf("validation failed: %s (%s)", e.Message, strings.Join(fields, "; "))
+}
+
+// AuthError is returned when the credentials are missing, invalid or lack the
+// permissions for the operation.
+type AuthError struct {
+	// StatusCode is either http.StatusUnauthorized or http.StatusForbidden.
+	StatusCode int
+	Message    string
+}
+
+func (e *AuthError) Error() string {
+	if e.Forbidden() {
+		return "forbidden: " + e.Message
+	}
+	return "unauthorized: " + e.Message
+}
+
+// Forbidden reports whether the credentials were valid but not allowed to
+// perform the operation.
+func (e *AuthError) Forbidden() bool {
+	return e.StatusCode == http.StatusForbidden
+}
+
+// RateLimitedError is returned when Atlassian throttled the request.
+type RateLimitedError struct {
+	// RetryAfter is how long the server asked us to wait, zero if unknown.
+	RetryAfter time.Duration
+	Message    string
+}
+
+func (e *RateLimitedError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("rate limited, retry after %s: %s", e.RetryAfter, e.Message)
+	}
+	return "rate limited: " + e.Message
+}
+
+// ServerError is returned for transient server side failures.
+type ServerError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *ServerError) Error() string {
+	return fmt.Sprintf("server error (%d): %s", e.StatusCode, e.Message)
+}
+
+// mutationError is the error shape of the JSM mutation payloads.
+type mutationError struct {
+	Message    string `json:"message"`
+	Extensions struct {
+		StatusCode int    `json:"statusCode"`
+		ErrorType  string `json:"errorType"`
+	} `json:"extensions"`
+}
+
+// payloadError converts the errors of an unsuccessful mutation payload into a
+// typed error. Only the first error determines the type, the messages of all
+// of them are kept.
+func payloadError(op string, errs []mutationError) error {
+	if len(errs) == 0 {
+		return fmt.Errorf("%s: mutation was not successful", op)
+	}
+	messages := make([]string, 0, len(errs))
+	for _, e := range errs {
+		messages = append(messages, e.Message)
+	}
+	first := errs[0]
+	typed := errorFromDetails(first.Extensions.StatusCode, first.Extensions.ErrorType, "", strings.Join(messages, "; "), nil)
+	return fmt.Errorf("%s: %w", op, typed)
+}
+
+// classifyError converts an error returned by the GraphQL client into one of
+// the typed errors above. Errors that can't be classified are only wrapped
+// with the operation name.
+func classifyError(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	// HTTP level failures are already typed by the caller
+	var typed interface{ jsmError() }
+	if errors.As(err, &typed) {
+		return fmt.Errorf("%s: %w", op, typed.(error))
+	}
+
+	var gqlErrs graphql.Errors
+	if errors.As(err, &gqlErrs) && len(gqlErrs) > 0 {
+		e := gqlErrs[0]
+		statusCode, _ := e.Extensions["statusCode"].(float64)
+		errorType, _ := e.Extensions["errorType"].(string)
+		classification, _ := e.Extensions["classification"].(string)
+		var fields []FieldError
+		if len(e.Path) > 0 {
+			path := make([]string, 0, len(e.Path))
+			for _, p := range e.Path {
+				path = append(path, fmt.Sprint(p))
+			}
+			fields = append(fields, FieldError{Path: strings.Join(path, "."), Message: e.Message})
+		}
+		if typed := errorFromDetails(int(statusCode), errorType, classification, e.Message, fields); typed != nil {
+			return fmt.Errorf("%s: %w", op, typed)
+		}
+	}
+
+	return fmt.Errorf("%s: %w", op, err)
+}
+
+// errorFromDetails maps the status code and error type reported by JSM to a
+// typed error. It returns nil if the details don't match any known type.
+func errorFromDetails(statusCode int, errorType, classification, message string, fields []FieldError) error {
+	errorType = strings.ToUpper(errorType)
+	switch {
+	case strings.Contains(message, revisionConflictMessage) || errorType == "REVISION_CONFLICT":
+		return &RevisionConflictError{Message: message}
+	case errorType == "ALREADY_EXISTS" || errorType == "DUPLICATE":
+		return &AlreadyExistsError{Message: message}
+	case statusCode == http.StatusConflict:
+		return &AlreadyExistsError{Message: message}
+	case statusCode == http.StatusNotFound || errorType == "NOT_FOUND":
+		return &NotFoundError{Message: message}
+	case statusCode == http.StatusUnauthorized || errorType == "UNAUTHENTICATED" || errorType == "UNAUTHORIZED":
+		return &AuthError{StatusCode: http.StatusUnauthorized, Message: message}
+	case statusCode == http.StatusForbidden || errorType == "FORBIDDEN":
+		return &AuthError{StatusCode: http.StatusForbidden, Message: message}
+	case statusCode == http.StatusTooManyRequests || errorType == "RATE_LIMITED":
+		return &RateLimitedError{Message: message}
+	case statusCode == http.StatusBadRequest || statusCode == http.StatusUnprocessableEntity ||
+		errorType == "BAD_REQUEST" || errorType == "VALIDATION_ERROR" || classification == "ValidationError":
+		return &ValidationError{Message: message, Fields: fields}
+	case statusCode >= http.StatusInternalServerError || errorType == "INTERNAL_SERVER_ERROR":
+		if statusCode == 0 {
+			statusCode = http.StatusInternalServerError
+		}
+		return &ServerError{StatusCode: statusCode, Message: message}
+	}
+	return nil
+}
+
+// errorFromResponse builds a typed error out of a failed HTTP response.
+func errorFromResponse(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return statusError(resp.StatusCode, string(body), resp.Header)
+}
+
+// statusError builds a typed error out of the status code, body and headers
+// of a failed HTTP response.
+func statusError(statusCode int, body string, header http.Header) error {
+	message := strings.TrimSpace(body)
+	if message == "" {
+		message = fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode))
+	}
+
+	typed := errorFromDetails(statusCode, "", "", message, nil)
+	if rl, ok := typed.(*RateLimitedError); ok {
+		rl.RetryAfter = parseRetryAfter(header.Get("Retry-After"), time.Now())
+	}
+	if typed == nil {
+		typed = fmt.Errorf("unexpected HTTP status %d: %s", statusCode, message)
+	}
+	return typed
+}
+
+// parseRetryAfter understands both forms of the Retry-After header: a number
+// of seconds and an HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
+
+type failedResponseKey struct{}
+
+// failedResponse holds the headers of the last failed response of a GraphQL
+// call. The GraphQL client only reports the status code and body of it.
+type failedResponse struct {
+	header http.Header
+}
+
+// withFailedResponse returns a context whose failed responses are recorded by
+// failedResponseTransport.
+func withFailedResponse(ctx context.Context) (context.Context, *failedResponse) {
+	failed := &failedResponse{}
+	return context.WithValue(ctx, failedResponseKey{}, failed), failed
+}
+
+// typed turns an error status reported by the GraphQL client into a typed
+// error, so both the GraphQL and the REST client report the same errors.
+func (f *failedResponse) typed(err error) error {
+	var netErr graphql.NetworkError
+	if !errors.As(err, &netErr) {
+		return err
+	}
+	return statusError(netErr.StatusCode(), netErr.Body(), f.header)
+}
+
+// failedResponseTransport records the headers of failed responses in the
+// failedResponse of the request context, if there is one.
+type failedResponseTransport struct {
+	base http.RoundTripper
+}
+
+func (t *failedResponseTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if failed, ok := req.Context().Value(failedResponseKey{}).(*failedResponse); ok && err == nil &&
+		resp.StatusCode >= http.StatusBadRequest {
+		failed.header = resp.Header
+	}
+	return resp, err
+}
+
+func (*RevisionConflictError) jsmError() {}
+func (*NotFoundError) jsmError()         {}
+func (*AlreadyExistsError) jsmError()    {}
+func (*ValidationError) jsmError()       {}
+func (*AuthError) jsmError()             {}
+func (*RateLimitedError) jsmError()      {}
+func (*ServerError) jsmError()           {}
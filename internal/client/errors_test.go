@@ -0,0 +1,118 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Typed errors", func() {
+	var server *httptest.Server
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	serve := func(status int, header map[string]string, body string) *JSMClient {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for k, v := range header {
+				w.Header().Set(k, v)
+			}
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(body))
+		}))
+		return newTestClient(server.URL)
+	}
+
+	It("reports HTTP 429 as rate limited with the Retry-After delay", func() {
+		c := serve(http.StatusTooManyRequests, map[string]string{"Retry-After": "7"}, "slow down")
+
+		_, err := c.GetServiceByName(context.Background(), "api")
+		var rateLimited *RateLimitedError
+		Expect(errors.As(err, &rateLimited)).To(BeTrue())
+		Expect(rateLimited.RetryAfter).To(Equal(7 * time.Second))
+	})
+
+	It("reports the REST API's HTTP 429 as rate limited with the Retry-After delay", func() {
+		c := serve(http.StatusTooManyRequests, map[string]string{"Retry-After": "7"}, `{"message":"slow down"}`)
+
+		_, err := c.GetOpsgenieTeam(context.Background(), "ari:cloud:opsgenie::team/cloud/sre")
+		var rateLimited *RateLimitedError
+		Expect(errors.As(err, &rateLimited)).To(BeTrue())
+		Expect(rateLimited.RetryAfter).To(Equal(7 * time.Second))
+	})
+
+	It("hands failed responses to the caller unchanged", func() {
+		c := serve(http.StatusNotFound, nil, "missing")
+		transport := &failedResponseTransport{base: http.DefaultTransport}
+
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := transport.RoundTrip(req)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+
+		Expect(c.DeleteOpsgenieTeam(context.Background(), "ari:cloud:opsgenie::team/cloud/sre")).To(
+			MatchError(ContainSubstring("not found: missing")))
+	})
+
+	DescribeTable("maps HTTP status codes",
+		func(status int, target any) {
+			c := serve(status, nil, "boom")
+			_, err := c.GetServiceByName(context.Background(), "api")
+			Expect(errors.As(err, target)).To(BeTrue(), "got %v", err)
+		},
+		Entry("unauthorized", http.StatusUnauthorized, new(*AuthError)),
+		Entry("forbidden", http.StatusForbidden, new(*AuthError)),
+		Entry("not found", http.StatusNotFound, new(*NotFoundError)),
+		Entry("bad gateway", http.StatusBadGateway, new(*ServerError)),
+	)
+
+	It("maps GraphQL error extensions", func() {
+		c := serve(http.StatusOK, nil, `{"errors":[{"message":"Invalid tier","path":["input","serviceTier"],`+
+			`"extensions":{"statusCode":400,"errorType":"BAD_REQUEST"}}]}`)
+
+		_, err := c.GetServiceByName(context.Background(), "api")
+		var validation *ValidationError
+		Expect(errors.As(err, &validation)).To(BeTrue())
+		Expect(validation.Fields).To(ConsistOf(FieldError{Path: "input.serviceTier", Message: "Invalid tier"}))
+	})
+
+	It("maps unsuccessful mutation payloads", func() {
+		c := serve(http.StatusOK, nil, `{"data":{"updateDevOpsService":{"success":false,"errors":[`+
+			`{"message":"Specified revision was incorrect","extensions":{"statusCode":409}}]}}}`)
+
+		_, err := c.UpdateService(context.Background(), &UpdateServiceRequest{ID: "ari:service/api", Revision: "1"})
+		var conflict *RevisionConflictError
+		Expect(errors.As(err, &conflict)).To(BeTrue())
+	})
+
+	It("parses both forms of Retry-After", func() {
+		now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		Expect(parseRetryAfter("3", now)).To(Equal(3 * time.Second))
+		Expect(parseRetryAfter(now.Add(time.Minute).Format(http.TimeFormat), now)).To(Equal(time.Minute))
+		Expect(parseRetryAfter("soon", now)).To(BeZero())
+	})
+})
@@ -0,0 +1,285 @@
+package fake
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// field is a single field of a GraphQL selection set.
+type field struct {
+	Name      string
+	Arguments map[string]any
+	Selection []field
+}
+
+// parser is a deliberately small GraphQL document parser. It understands the
+// subset produced by the go-graphql-client query builder: an optional
+// operation header with variable definitions, nested selection sets and
+// arguments with literal, variable, object and list values.
+type parser struct {
+	src       string
+	pos       int
+	variables map[string]any
+}
+
+// parseOperation parses a GraphQL operation and returns its top level
+// selection set with all variables substituted.
+func parseOperation(query string, variables map[string]any) ([]field, error) {
+	p := &parser{src: query, variables: variables}
+	p.skipSpace()
+	// skip the operation header, variable definitions live in parentheses
+	for p.pos < len(p.src) && p.src[p.pos] != '{' {
+		if p.src[p.pos] == '(' {
+			if err := p.skipBalanced('(', ')'); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		p.pos++
+	}
+	return p.selectionSet()
+}
+
+func (p *parser) selectionSet() ([]field, error) {
+	if err := p.expect('{'); err != nil {
+		return nil, err
+	}
+	var fields []field
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.src) {
+			return nil, fmt.Errorf("unterminated selection set")
+		}
+		if p.src[p.pos] == '}' {
+			p.pos++
+			return fields, nil
+		}
+		f, err := p.field()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+}
+
+func (p *parser) field() (field, error) {
+	f := field{Name: p.name()}
+	if f.Name == "" {
+		return f, fmt.Errorf("expected field name at offset %d", p.pos)
+	}
+	p.skipSpace()
+	if p.peek('(') {
+		args, err := p.arguments()
+		if err != nil {
+			return f, err
+		}
+		f.Arguments = args
+		p.skipSpace()
+	}
+	if p.peek('{') {
+		selection, err := p.selectionSet()
+		if err != nil {
+			return f, err
+		}
+		f.Selection = selection
+	}
+	return f, nil
+}
+
+func (p *parser) arguments() (map[string]any, error) {
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+	return p.pairs(')')
+}
+
+// pairs parses "key: value" pairs until the closing delimiter.
+func (p *parser) pairs(closing byte) (map[string]any, error) {
+	out := map[string]any{}
+	for {
+		p.skipSpace()
+		if p.peek(closing) {
+			p.pos++
+			return out, nil
+		}
+		key := p.name()
+		if key == "" {
+			return nil, fmt.Errorf("expected argument name at offset %d", p.pos)
+		}
+		if err := p.expect(':'); err != nil {
+			return nil, err
+		}
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		out[key] = value
+	}
+}
+
+func (p *parser) value() (any, error) {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return nil, fmt.Errorf("unexpected end of document")
+	}
+	switch c := p.src[p.pos]; {
+	case c == '$':
+		p.pos++
+		return p.variables[p.name()], nil
+	case c == '{':
+		p.pos++
+		return p.pairs('}')
+	case c == '[':
+		p.pos++
+		var list []any
+		for {
+			p.skipSpace()
+			if p.peek(']') {
+				p.pos++
+				return list, nil
+			}
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+	case c == '"':
+		end := p.pos + 1
+		for end < len(p.src) && (p.src[end] != '"' || p.src[end-1] == '\\') {
+			end++
+		}
+		s, err := strconv.Unquote(p.src[p.pos : end+1])
+		p.pos = end + 1
+		return s, err
+	default:
+		word := p.name()
+		switch word {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		if n, err := strconv.ParseFloat(word, 64); err == nil {
+			return n, nil
+		}
+		// enum value
+		return word, nil
+	}
+}
+
+func (p *parser) name() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.src) {
+		r := rune(p.src[p.pos])
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-' && r != '.' {
+			break
+		}
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *parser) skipBalanced(open, closing byte) error {
+	depth := 0
+	for ; p.pos < len(p.src); p.pos++ {
+		switch p.src[p.pos] {
+		case open:
+			depth++
+		case closing:
+			depth--
+			if depth == 0 {
+				p.pos++
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("unbalanced %q", open)
+}
+
+func (p *parser) expect(c byte) error {
+	p.skipSpace()
+	if !p.peek(c) {
+		return fmt.Errorf("expected %q at offset %d", c, p.pos)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *parser) peek(c byte) bool {
+	return p.pos < len(p.src) && p.src[p.pos] == c
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.src) && (unicode.IsSpace(rune(p.src[p.pos])) || p.src[p.pos] == ',') {
+		p.pos++
+	}
+}
+
+// project keeps only the selected fields of a resolved value, the same way a
+// real GraphQL server only returns what was asked for.
+func project(value any, selection []field) any {
+	if len(selection) == 0 {
+		return value
+	}
+	switch v := value.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(selection))
+		for _, f := range selection {
+			out[f.Name] = project(v[f.Name], f.Selection)
+		}
+		return out
+	case []map[string]any:
+		out := make([]any, 0, len(v))
+		for _, item := range v {
+			out = append(out, project(item, selection))
+		}
+		return out
+	case []any:
+		out := make([]any, 0, len(v))
+		for _, item := range v {
+			out = append(out, project(item, selection))
+		}
+		return out
+	default:
+		return value
+	}
+}
+
+// stringArg returns a string argument, following dotted paths into objects.
+func stringArg(args map[string]any, path string) string {
+	var cur any = args
+	for _, key := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return ""
+		}
+		cur = m[key]
+	}
+	s, _ := cur.(string)
+	return s
+}
+
+// intArg returns a numeric argument, following dotted paths into objects.
+func intArg(args map[string]any, path string) int {
+	var cur any = args
+	for _, key := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return 0
+		}
+		cur = m[key]
+	}
+	switch n := cur.(type) {
+	case float64:
+		return int(n)
+	case int:
+		return n
+	}
+	return 0
+}
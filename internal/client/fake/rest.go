@@ -0,0 +1,203 @@
+package fake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// restTeam is the body of a team create or update, and the team in responses.
+// An update without members keeps the current ones.
+type restTeam struct {
+	ID          string            `json:"id,omitempty"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Members     *[]restTeamMember `json:"members,omitempty"`
+}
+
+type restTeamMember struct {
+	User struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	Role string `json:"role,omitempty"`
+}
+
+// serveTeams serves the subset of the Opsgenie REST teams API used by the
+// operator. Teams are addressed by the last segment of their ARI.
+func (s *Server) serveTeams(w http.ResponseWriter, r *http.Request) {
+	_, rest, _ := strings.Cut(r.URL.Path, "/v1/teams")
+	teamID := strings.Trim(rest, "/")
+
+	var op string
+	switch {
+	case teamID == "" && r.Method == http.MethodPost:
+		op = "createTeam"
+	case teamID != "" && r.Method == http.MethodGet:
+		op = "getTeam"
+	case teamID != "" && r.Method == http.MethodPatch:
+		op = "updateTeam"
+	case teamID != "" && r.Method == http.MethodDelete:
+		op = "deleteTeam"
+	default:
+		restError(w, http.StatusMethodNotAllowed, fmt.Sprintf("fake: unsupported request %s %s", r.Method, r.URL.Path))
+		return
+	}
+
+	var body restTeam
+	if r.Method == http.MethodPost || r.Method == http.MethodPatch {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			restError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls[op]++
+	injected, failing := s.popFailure(op)
+	if failing && !injected.applied {
+		restError(w, injected.statusCode, injected.message)
+		return
+	}
+
+	status, resp := s.resolveTeam(op, s.teamARI(teamID), body)
+	if failing {
+		restError(w, injected.statusCode, injected.message)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (s *Server) resolveTeam(op, id string, body restTeam) (int, map[string]any) {
+	if op == "createTeam" {
+		return s.createTeam(body)
+	}
+
+	team := s.teamByID(id)
+	if team == nil {
+		return http.StatusNotFound, map[string]any{"message": fmt.Sprintf("No team exists with id [%s]", id)}
+	}
+	switch op {
+	case "getTeam":
+		return http.StatusOK, map[string]any{"data": teamBody(team)}
+	case "updateTeam":
+		if body.Name != "" && body.Name != team.Name && s.teamByName(body.Name) != nil {
+			return http.StatusConflict, map[string]any{"message": fmt.Sprintf("Team with name [%s] already exists", body.Name)}
+		}
+		if body.Members != nil {
+			members, err := teamMembers(*body.Members)
+			if err != nil {
+				return http.StatusUnprocessableEntity, map[string]any{"message": err.Error()}
+			}
+			team.Members = members
+		}
+		if body.Name != "" {
+			team.Name = body.Name
+		}
+		team.Description = body.Description
+		return http.StatusOK, map[string]any{"result": "Updated", "data": map[string]any{"id": restID(team.ID), "name": team.Name}}
+	default:
+		s.teams = removeTeam(s.teams, id)
+		return http.StatusOK, map[string]any{"result": "Deleted"}
+	}
+}
+
+func (s *Server) createTeam(body restTeam) (int, map[string]any) {
+	if body.Name == "" {
+		return http.StatusUnprocessableEntity, map[string]any{"message": "Team name must not be empty"}
+	}
+	if s.teamByName(body.Name) != nil {
+		return http.StatusConflict, map[string]any{"message": fmt.Sprintf("Team with name [%s] already exists", body.Name)}
+	}
+	var members []TeamMember
+	if body.Members != nil {
+		var err error
+		if members, err = teamMembers(*body.Members); err != nil {
+			return http.StatusUnprocessableEntity, map[string]any{"message": err.Error()}
+		}
+	}
+
+	team := Team{
+		ID:          s.teamARI(s.nextID()),
+		Name:        body.Name,
+		Description: body.Description,
+		Members:     members,
+	}
+	s.teams = append(s.teams, team)
+	return http.StatusCreated, map[string]any{"result": "Added", "data": map[string]any{"id": restID(team.ID), "name": team.Name}}
+}
+
+// teamMembers validates the members of a request like Opsgenie does: every
+// member needs a username and the role defaults to user.
+func teamMembers(in []restTeamMember) ([]TeamMember, error) {
+	members := make([]TeamMember, 0, len(in))
+	for _, m := range in {
+		role := m.Role
+		if role == "" {
+			role = "user"
+		}
+		if m.User.Username == "" {
+			return nil, errors.New("team member must have a username")
+		}
+		if role != "admin" && role != "user" {
+			return nil, fmt.Errorf("invalid team member role [%s]", role)
+		}
+		members = append(members, TeamMember{Username: m.User.Username, Role: role})
+	}
+	return members, nil
+}
+
+func teamBody(team *Team) restTeam {
+	members := make([]restTeamMember, 0, len(team.Members))
+	for _, m := range team.Members {
+		var member restTeamMember
+		member.User.Username = m.Username
+		member.Role = m.Role
+		members = append(members, member)
+	}
+	return restTeam{
+		ID:          restID(team.ID),
+		Name:        team.Name,
+		Description: team.Description,
+		Members:     &members,
+	}
+}
+
+func restError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(map[string]any{"message": message})
+}
+
+func (s *Server) teamByName(name string) *Team {
+	for i := range s.teams {
+		if s.teams[i].Name == name {
+			return &s.teams[i]
+		}
+	}
+	return nil
+}
+
+func removeTeam(teams []Team, id string) []Team {
+	out := teams[:0]
+	for _, team := range teams {
+		if team.ID != id {
+			out = append(out, team)
+		}
+	}
+	return out
+}
+
+func (s *Server) teamARI(teamID string) string {
+	return fmt.Sprintf("ari:cloud:opsgenie::team/%s/%s", s.cloudID, teamID)
+}
+
+// restID returns the ID the REST API knows a team by.
+func restID(ari string) string {
+	return ari[strings.LastIndex(ari, "/")+1:]
+}
@@ -0,0 +1,693 @@
+// Package fake provides an in-process stand-in for the JSM GraphQL API and the
+// Opsgenie REST teams API, so the client and the reconcilers can be exercised
+// end to end without Atlassian.
+//
+// Point a JSMClient at Server.URL to use it:
+//
+//	server := fake.NewServer("cloud-id")
+//	defer server.Close()
+//	c, _ := client.NewJSMClient(client.JSMConfig{GraphQLURL: server.URL, RestURL: server.URL, ...})
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// RevisionConflictMessage is what JSM answers to an update with a stale revision.
+const RevisionConflictMessage = "Specified revision was incorrect"
+
+// Service is the state of a service held by the fake backend.
+type Service struct {
+	ID             string
+	Name           string
+	Description    string
+	Revision       string
+	TierID         string
+	TierLevel      int
+	ServiceType    string
+	ResponderTeams []string
+}
+
+// Tier is a service tier known to the fake backend.
+type Tier struct {
+	ID          string
+	Level       int
+	Name        string
+	Description string
+}
+
+// Team is an Opsgenie team known to the fake backend.
+type Team struct {
+	ID          string
+	Name        string
+	Description string
+	Members     []TeamMember
+}
+
+// TeamMember is a user in an Opsgenie team.
+type TeamMember struct {
+	Username string
+	Role     string
+}
+
+// Relationship links a service with an Opsgenie team.
+type Relationship struct {
+	ID        string
+	ServiceID string
+	TeamID    string
+}
+
+// injectedError is returned instead of resolving the next call of a field.
+type injectedError struct {
+	statusCode int
+	message    string
+	// applied requests take effect before the error is returned
+	applied bool
+}
+
+// Server is an httptest server that speaks the subset of the JSM GraphQL API
+// used by the operator. Every update bumps the service revision and updates
+// carrying a stale revision are rejected, like the real API does.
+type Server struct {
+	*httptest.Server
+
+	// PageSize caps the number of edges returned per connection page.
+	PageSize int
+
+	cloudID string
+
+	mu            sync.Mutex
+	seq           int
+	services      map[string]*Service
+	tiers         []Tier
+	teams         []Team
+	relationships map[string]Relationship
+	calls         map[string]int
+	failures      map[string][]injectedError
+}
+
+// NewServer starts a fake JSM backend for the given cloud ID. It comes with
+// the four default service tiers and no services or teams.
+func NewServer(cloudID string) *Server {
+	s := &Server{
+		PageSize:      50,
+		cloudID:       cloudID,
+		services:      map[string]*Service{},
+		relationships: map[string]Relationship{},
+		calls:         map[string]int{},
+		failures:      map[string][]injectedError{},
+	}
+	for level, name := range []string{"Critical", "High", "Medium", "Low"} {
+		s.tiers = append(s.tiers, Tier{
+			ID:          fmt.Sprintf("ari:cloud:graph::service-tier/%s/%d", cloudID, level+1),
+			Level:       level + 1,
+			Name:        name,
+			Description: fmt.Sprintf("Tier %d service", level+1),
+		})
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// AddTeam registers an Opsgenie team and returns its ARI.
+func (s *Server) AddTeam(name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.teamARI(s.nextID())
+	s.teams = append(s.teams, Team{ID: id, Name: name})
+	return id
+}
+
+// Team returns a copy of the Opsgenie team with the given ARI.
+func (s *Server) Team(id string) (Team, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	team := s.teamByID(id)
+	if team == nil {
+		return Team{}, false
+	}
+	out := *team
+	out.Members = append([]TeamMember(nil), team.Members...)
+	return out, true
+}
+
+// EditTeam changes an Opsgenie team behind the operator's back, like an edit
+// in the Opsgenie UI would.
+func (s *Server) EditTeam(id string, edit func(*Team)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if team := s.teamByID(id); team != nil {
+		edit(team)
+	}
+}
+
+// AddService registers a service as if it had been created outside of the
+// operator, e.g. in the JSM UI, and returns a copy of it.
+func (s *Server) AddService(svc Service) Service {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if svc.ID == "" {
+		svc.ID = s.serviceARI()
+	}
+	if svc.Revision == "" {
+		svc.Revision = "1"
+	}
+	if svc.TierLevel != 0 && svc.TierID == "" {
+		svc.TierID = s.tierByLevel(svc.TierLevel).ID
+	}
+	s.services[svc.ID] = &svc
+	return svc
+}
+
+// EditService changes a service behind the operator's back and bumps its
+// revision, like an edit in the JSM UI would.
+func (s *Server) EditService(id string, edit func(*Service)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	svc, ok := s.services[id]
+	if !ok {
+		return
+	}
+	edit(svc)
+	bumpRevision(svc)
+}
+
+// Service returns a copy of the service with the given ARI.
+func (s *Server) Service(id string) (Service, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	svc, ok := s.services[id]
+	if !ok {
+		return Service{}, false
+	}
+	return *svc, true
+}
+
+// Services returns a copy of all services ordered by ARI.
+func (s *Server) Services() []Service {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]Service, 0, len(s.services))
+	for _, svc := range s.sortedServices() {
+		out = append(out, *svc)
+	}
+	return out
+}
+
+// Relationships returns all service to team relationships of a service.
+func (s *Server) Relationships(serviceID string) []Relationship {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []Relationship
+	for _, rel := range s.sortedRelationships() {
+		if rel.ServiceID == serviceID {
+			out = append(out, rel)
+		}
+	}
+	return out
+}
+
+// Calls returns how many times the given root field, e.g.
+// "createDevOpsService", or REST operation, e.g. "createTeam", was requested.
+func (s *Server) Calls(field string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.calls[field]
+}
+
+// FailNext makes the next request of the given root field fail with a GraphQL
+// error carrying the status code in its extensions. A REST operation fails
+// with the status code itself.
+func (s *Server) FailNext(field string, statusCode int, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures[field] = append(s.failures[field], injectedError{statusCode: statusCode, message: message})
+}
+
+// LoseNextResponse makes the next request of the given root field take
+// effect, but answer with a 504 as if the response got lost on the way, e.g.
+// because the caller died.
+func (s *Server) LoseNextResponse(field string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures[field] = append(s.failures[field], injectedError{
+		statusCode: http.StatusGatewayTimeout,
+		message:    "Gateway Timeout",
+		applied:    true,
+	})
+}
+
+type graphQLRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+type graphQLError struct {
+	Message    string         `json:"message"`
+	Path       []any          `json:"path,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+// serve routes the REST teams API and sends everything else to GraphQL.
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	if strings.Contains(r.URL.Path, "/v1/teams") {
+		s.serveTeams(w, r)
+		return
+	}
+	s.serveGraphQL(w, r)
+}
+
+func (s *Server) serveGraphQL(w http.ResponseWriter, r *http.Request) {
+	var req graphQLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	fields, err := parseOperation(req.Query, req.Variables)
+	if err != nil {
+		writeJSON(w, map[string]any{"errors": []graphQLError{{Message: err.Error()}}})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data := map[string]any{}
+	var errs []graphQLError
+	for _, f := range fields {
+		s.calls[f.Name]++
+		if injected, ok := s.popFailure(f.Name); ok {
+			if injected.applied {
+				_, _ = s.resolve(f)
+			}
+			errs = append(errs, graphQLError{
+				Message:    injected.message,
+				Path:       []any{f.Name},
+				Extensions: map[string]any{"statusCode": injected.statusCode},
+			})
+			data[f.Name] = nil
+			continue
+		}
+
+		value, err := s.resolve(f)
+		if err != nil {
+			errs = append(errs, graphQLError{Message: err.Error(), Path: []any{f.Name}})
+			data[f.Name] = nil
+			continue
+		}
+		data[f.Name] = project(value, f.Selection)
+	}
+
+	resp := map[string]any{"data": data}
+	if len(errs) > 0 {
+		resp["errors"] = errs
+	}
+	writeJSON(w, resp)
+}
+
+func (s *Server) popFailure(field string) (injectedError, bool) {
+	queue := s.failures[field]
+	if len(queue) == 0 {
+		return injectedError{}, false
+	}
+	s.failures[field] = queue[1:]
+	return queue[0], true
+}
+
+func (s *Server) resolve(f field) (any, error) {
+	switch f.Name {
+	case "devOpsServices":
+		return s.devOpsServices(f.Arguments), nil
+	case "devOpsService":
+		return s.devOpsService(f), nil
+	case "devOpsServiceTiers":
+		return s.devOpsServiceTiers(), nil
+	case "createDevOpsService":
+		return s.createDevOpsService(f.Arguments), nil
+	case "updateDevOpsService":
+		return s.updateDevOpsService(f.Arguments), nil
+	case "deleteDevOpsService":
+		return s.deleteDevOpsService(f.Arguments), nil
+	case "createDevOpsServiceAndOpsgenieTeamRelationship":
+		return s.createRelationship(f.Arguments), nil
+	case "deleteDevOpsServiceAndOpsgenieTeamRelationship":
+		return s.deleteRelationship(f.Arguments), nil
+	case "opsgenie":
+		out := map[string]any{}
+		for _, sub := range f.Selection {
+			switch sub.Name {
+			case "allOpsgenieTeams":
+				out[sub.Name] = s.allOpsgenieTeams(sub.Arguments)
+			default:
+				return nil, fmt.Errorf("fake: unsupported field opsgenie.%s", sub.Name)
+			}
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("fake: unsupported field %s", f.Name)
+}
+
+func (s *Server) devOpsServices(args map[string]any) map[string]any {
+	nameContains := stringArg(args, "filter.nameContains")
+	var matched []map[string]any
+	for _, svc := range s.sortedServices() {
+		if nameContains != "" && !strings.Contains(strings.ToLower(svc.Name), strings.ToLower(nameContains)) {
+			continue
+		}
+		matched = append(matched, serviceNode(svc))
+	}
+	return s.connection(matched, args)
+}
+
+// devOpsService returns the full state of a single service, or nil if there
+// is no service with the requested ARI.
+func (s *Server) devOpsService(f field) any {
+	svc, ok := s.services[stringArg(f.Arguments, "id")]
+	if !ok {
+		return nil
+	}
+
+	node := serviceNode(svc)
+	if tier := s.tierByID(svc.TierID); tier != nil {
+		node["serviceTier"].(map[string]any)["name"] = tier.Name
+	}
+	node["properties"] = []map[string]any{{
+		"key":   "responders",
+		"value": map[string]any{"teams": svc.ResponderTeams},
+	}}
+
+	var relationships []map[string]any
+	for _, rel := range s.sortedRelationships() {
+		if rel.ServiceID == svc.ID {
+			relationships = append(relationships, map[string]any{"id": rel.ID, "opsgenieTeamId": rel.TeamID})
+		}
+	}
+	var args map[string]any
+	for _, sub := range f.Selection {
+		if sub.Name == "opsgenieTeamRelationships" {
+			args = sub.Arguments
+		}
+	}
+	node["opsgenieTeamRelationships"] = s.connection(relationships, args)
+	return node
+}
+
+func (s *Server) allOpsgenieTeams(args map[string]any) map[string]any {
+	nodes := make([]map[string]any, 0, len(s.teams))
+	for _, team := range s.teams {
+		nodes = append(nodes, map[string]any{"id": team.ID, "name": team.Name})
+	}
+	return s.connection(nodes, args)
+}
+
+// connection pages through nodes using opaque "offset" cursors.
+func (s *Server) connection(nodes []map[string]any, args map[string]any) map[string]any {
+	offset := 0
+	if after := stringArg(args, "after"); after != "" {
+		offset, _ = strconv.Atoi(strings.TrimPrefix(after, "cursor-"))
+	}
+	limit := s.PageSize
+	if first := intArg(args, "first"); first > 0 && first < limit {
+		limit = first
+	}
+	end := min(offset+limit, len(nodes))
+	offset = min(offset, end)
+
+	edges := make([]map[string]any, 0, end-offset)
+	for _, node := range nodes[offset:end] {
+		edges = append(edges, map[string]any{"node": node})
+	}
+	return map[string]any{
+		"edges": edges,
+		"pageInfo": map[string]any{
+			"hasNextPage": end < len(nodes),
+			"endCursor":   fmt.Sprintf("cursor-%d", end),
+		},
+	}
+}
+
+func (s *Server) devOpsServiceTiers() []map[string]any {
+	out := make([]map[string]any, 0, len(s.tiers))
+	for _, tier := range s.tiers {
+		out = append(out, map[string]any{
+			"id":          tier.ID,
+			"level":       tier.Level,
+			"name":        tier.Name,
+			"description": tier.Description,
+		})
+	}
+	return out
+}
+
+func (s *Server) createDevOpsService(args map[string]any) map[string]any {
+	name := stringArg(args, "input.name")
+	if name == "" {
+		return mutationFailure(http.StatusBadRequest, "BAD_REQUEST", "Service name must not be empty")
+	}
+	if s.nameTaken(name, "") {
+		return mutationFailure(http.StatusConflict, "ALREADY_EXISTS", fmt.Sprintf("Service with name %q already exists", name))
+	}
+	tier := s.tierByLevel(intArg(args, "input.serviceTier.level"))
+	if tier == nil {
+		return mutationFailure(http.StatusBadRequest, "BAD_REQUEST", "Unknown service tier")
+	}
+
+	svc := &Service{
+		ID:             s.serviceARI(),
+		Name:           name,
+		Description:    stringArg(args, "input.description"),
+		Revision:       "1",
+		TierID:         tier.ID,
+		TierLevel:      tier.Level,
+		ServiceType:    stringArg(args, "input.serviceType.key"),
+		ResponderTeams: responderTeams(args),
+	}
+	s.services[svc.ID] = svc
+	return map[string]any{"success": true, "errors": []any{}, "service": serviceNode(svc)}
+}
+
+func (s *Server) updateDevOpsService(args map[string]any) map[string]any {
+	id := stringArg(args, "input.id")
+	svc, ok := s.services[id]
+	if !ok {
+		return mutationFailure(http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("Service %s not found", id))
+	}
+	if stringArg(args, "input.revision") != svc.Revision {
+		return mutationFailure(http.StatusConflict, "", RevisionConflictMessage)
+	}
+
+	name := stringArg(args, "input.name")
+	if name != "" && s.nameTaken(name, id) {
+		return mutationFailure(http.StatusConflict, "ALREADY_EXISTS", fmt.Sprintf("Service with name %q already exists", name))
+	}
+	var tier *Tier
+	if tierID := stringArg(args, "input.serviceTier"); tierID != "" {
+		if tier = s.tierByID(tierID); tier == nil {
+			return mutationFailure(http.StatusBadRequest, "BAD_REQUEST", "Unknown service tier")
+		}
+	}
+
+	if name != "" {
+		svc.Name = name
+	}
+	svc.Description = stringArg(args, "input.description")
+	if tier != nil {
+		svc.TierID = tier.ID
+		svc.TierLevel = tier.Level
+	}
+	if input, _ := args["input"].(map[string]any); input["properties"] != nil {
+		svc.ResponderTeams = responderTeams(args)
+	}
+	bumpRevision(svc)
+	return map[string]any{"success": true, "errors": []any{}, "service": serviceNode(svc)}
+}
+
+func (s *Server) deleteDevOpsService(args map[string]any) map[string]any {
+	id := stringArg(args, "input.id")
+	if _, ok := s.services[id]; !ok {
+		return mutationFailure(http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("Service %s not found", id))
+	}
+
+	delete(s.services, id)
+	for relID, rel := range s.relationships {
+		if rel.ServiceID == id {
+			delete(s.relationships, relID)
+		}
+	}
+	return map[string]any{"success": true, "errors": []any{}}
+}
+
+func (s *Server) createRelationship(args map[string]any) map[string]any {
+	serviceID := stringArg(args, "input.serviceId")
+	teamID := stringArg(args, "input.opsgenieTeamId")
+	if _, ok := s.services[serviceID]; !ok {
+		return mutationFailure(http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("Service %s not found", serviceID))
+	}
+	if s.teamByID(teamID) == nil {
+		return mutationFailure(http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("Opsgenie team %s not found", teamID))
+	}
+	for _, rel := range s.relationships {
+		if rel.ServiceID == serviceID && rel.TeamID == teamID {
+			return mutationFailure(http.StatusConflict, "ALREADY_EXISTS", "Relationship already exists")
+		}
+	}
+
+	rel := Relationship{
+		ID:        fmt.Sprintf("ari:cloud:graph::service-and-opsgenie-team-relationship/%s/%s", s.cloudID, s.nextID()),
+		ServiceID: serviceID,
+		TeamID:    teamID,
+	}
+	s.relationships[rel.ID] = rel
+	return map[string]any{
+		"success":                            true,
+		"errors":                             []any{},
+		"serviceAndOpsgenieTeamRelationship": map[string]any{"id": rel.ID},
+	}
+}
+
+func (s *Server) deleteRelationship(args map[string]any) map[string]any {
+	id := stringArg(args, "input.id")
+	if _, ok := s.relationships[id]; !ok {
+		return mutationFailure(http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("Relationship %s not found", id))
+	}
+
+	delete(s.relationships, id)
+	return map[string]any{"success": true, "errors": []any{}}
+}
+
+func serviceNode(svc *Service) map[string]any {
+	return map[string]any{
+		"id":          svc.ID,
+		"name":        svc.Name,
+		"description": svc.Description,
+		"revision":    svc.Revision,
+		"serviceTier": map[string]any{"id": svc.TierID, "level": svc.TierLevel},
+		"serviceType": map[string]any{"key": svc.ServiceType},
+	}
+}
+
+func mutationFailure(statusCode int, errorType, message string) map[string]any {
+	return map[string]any{
+		"success": false,
+		"errors": []map[string]any{{
+			"message":    message,
+			"extensions": map[string]any{"statusCode": statusCode, "errorType": errorType},
+		}},
+	}
+}
+
+// responderTeams extracts the teams of the "responders" property of an input.
+func responderTeams(args map[string]any) []string {
+	input, _ := args["input"].(map[string]any)
+	props, _ := input["properties"].([]any)
+	var teams []string
+	for _, p := range props {
+		prop, _ := p.(map[string]any)
+		if prop["key"] != "responders" {
+			continue
+		}
+		value, _ := prop["value"].(map[string]any)
+		list, _ := value["teams"].([]any)
+		for _, t := range list {
+			if team, ok := t.(string); ok {
+				teams = append(teams, team)
+			}
+		}
+	}
+	return teams
+}
+
+func bumpRevision(svc *Service) {
+	rev, _ := strconv.Atoi(svc.Revision)
+	svc.Revision = strconv.Itoa(rev + 1)
+}
+
+func (s *Server) nameTaken(name, exceptID string) bool {
+	for _, svc := range s.services {
+		if svc.ID != exceptID && svc.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) tierByLevel(level int) *Tier {
+	for i := range s.tiers {
+		if s.tiers[i].Level == level {
+			return &s.tiers[i]
+		}
+	}
+	return nil
+}
+
+func (s *Server) tierByID(id string) *Tier {
+	for i := range s.tiers {
+		if s.tiers[i].ID == id {
+			return &s.tiers[i]
+		}
+	}
+	return nil
+}
+
+func (s *Server) teamByID(id string) *Team {
+	for i := range s.teams {
+		if s.teams[i].ID == id {
+			return &s.teams[i]
+		}
+	}
+	return nil
+}
+
+func (s *Server) sortedServices() []*Service {
+	out := make([]*Service, 0, len(s.services))
+	for _, svc := range s.services {
+		out = append(out, svc)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+func (s *Server) sortedRelationships() []Relationship {
+	out := make([]Relationship, 0, len(s.relationships))
+	for _, rel := range s.relationships {
+		out = append(out, rel)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+func (s *Server) serviceARI() string {
+	return fmt.Sprintf("ari:cloud:graph::service/%s/%s", s.cloudID, s.nextID())
+}
+
+// nextID returns a monotonically increasing, zero padded ID so that ARIs
+// sort in creation order.
+func (s *Server) nextID() string {
+	s.seq++
+	return fmt.Sprintf("%06d", s.seq)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
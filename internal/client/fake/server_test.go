@@ -0,0 +1,286 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	jsmclient "github.com/artemlive/jsm-operator/internal/client"
+	"github.com/artemlive/jsm-operator/internal/client/fake"
+)
+
+var _ = Describe("Fake JSM backend", func() {
+	var (
+		ctx    context.Context
+		server *fake.Server
+		c      *jsmclient.JSMClient
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		server = fake.NewServer("cloud")
+		var err error
+		c, err = jsmclient.NewJSMClient(jsmclient.JSMConfig{
+			GraphQLURL: server.URL,
+			RestURL:    server.URL,
+			Token:      "token",
+			Username:   "user",
+			CloudID:    "cloud",
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("drives a create, update, conflict, relationship and delete flow", func() {
+		teamID := server.AddTeam("SRE")
+
+		created, err := c.CreateService(ctx, &jsmclient.CreateServiceRequest{
+			Name:        "api",
+			Description: "public api",
+			TierLevel:   2,
+			ServiceType: "APPLICATIONS",
+			TeamARNs:    []string{teamID},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created.Revision).To(Equal("1"))
+		Expect(created.TierLevel).To(Equal(2))
+
+		found, err := c.GetServiceByName(ctx, "api")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found.ID).To(Equal(created.ID))
+
+		tierID, err := c.GetTierIDByLevel(ctx, 1)
+		Expect(err).NotTo(HaveOccurred())
+
+		updated, err := c.UpdateService(ctx, &jsmclient.UpdateServiceRequest{
+			ID:          created.ID,
+			Revision:    created.Revision,
+			Name:        "api",
+			Description: "still public",
+			TierID:      tierID,
+			TeamARNs:    []string{teamID},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated.Revision).To(Equal("2"))
+		Expect(updated.TierLevel).To(Equal(1))
+
+		_, err = c.UpdateService(ctx, &jsmclient.UpdateServiceRequest{
+			ID:       created.ID,
+			Revision: created.Revision,
+			Name:     "api",
+		})
+		var conflict *jsmclient.RevisionConflictError
+		Expect(errors.As(err, &conflict)).To(BeTrue())
+
+		relID, err := c.CreateOpsgenieTeamRelationship(ctx, created.ID, teamID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(server.Relationships(created.ID)).To(ConsistOf(fake.Relationship{
+			ID: relID, ServiceID: created.ID, TeamID: teamID,
+		}))
+
+		_, err = c.CreateOpsgenieTeamRelationship(ctx, created.ID, teamID)
+		var exists *jsmclient.AlreadyExistsError
+		Expect(errors.As(err, &exists)).To(BeTrue())
+
+		Expect(c.DeleteService(ctx, created.ID)).To(Succeed())
+		_, ok := server.Service(created.ID)
+		Expect(ok).To(BeFalse())
+		Expect(server.Relationships(created.ID)).To(BeEmpty())
+
+		err = c.DeleteService(ctx, created.ID)
+		var notFound *jsmclient.NotFoundError
+		Expect(errors.As(err, &notFound)).To(BeTrue())
+	})
+
+	It("returns the full state of a service by ID", func() {
+		teamID := server.AddTeam("SRE")
+		created, err := c.CreateService(ctx, &jsmclient.CreateServiceRequest{
+			Name:        "api",
+			Description: "public api",
+			TierLevel:   2,
+			ServiceType: "APPLICATIONS",
+			TeamARNs:    []string{teamID},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		relID, err := c.CreateOpsgenieTeamRelationship(ctx, created.ID, teamID)
+		Expect(err).NotTo(HaveOccurred())
+
+		svc, err := c.GetServiceByID(ctx, created.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(svc.Name).To(Equal("api"))
+		Expect(*svc.Description).To(Equal("public api"))
+		Expect(svc.Revision).To(Equal(created.Revision))
+		Expect(svc.TierID).To(Equal(created.TierID))
+		Expect(svc.TierLevel).To(Equal(2))
+		Expect(svc.TierName).To(Equal("High"))
+		Expect(svc.ApplicationType).To(Equal("APPLICATIONS"))
+		Expect(svc.ResponderTeams).To(ConsistOf(teamID))
+		Expect(svc.TeamRelationships).To(ConsistOf(jsmclient.TeamRelationship{ID: relID, TeamID: teamID}))
+
+		_, err = c.GetServiceByID(ctx, "ari:cloud:graph::service/cloud/missing")
+		var notFound *jsmclient.NotFoundError
+		Expect(errors.As(err, &notFound)).To(BeTrue())
+	})
+
+	It("lists and deletes team relationships across pages", func() {
+		server.PageSize = 2
+		svc := server.AddService(fake.Service{Name: "api", TierLevel: 1})
+		var teamIDs []string
+		for _, name := range []string{"a", "b", "c"} {
+			teamID := server.AddTeam(name)
+			teamIDs = append(teamIDs, teamID)
+			_, err := c.CreateOpsgenieTeamRelationship(ctx, svc.ID, teamID)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		relationships, err := c.ListOpsgenieTeamRelationships(ctx, svc.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(relationships).To(HaveLen(3))
+		Expect(relationships[2].TeamID).To(Equal(teamIDs[2]))
+
+		Expect(c.DeleteOpsgenieTeamRelationship(ctx, relationships[0].ID)).To(Succeed())
+		Expect(server.Relationships(svc.ID)).To(HaveLen(2))
+
+		err = c.DeleteOpsgenieTeamRelationship(ctx, relationships[0].ID)
+		var notFound *jsmclient.NotFoundError
+		Expect(errors.As(err, &notFound)).To(BeTrue())
+
+		_, err = c.ListOpsgenieTeamRelationships(ctx, "ari:cloud:graph::service/cloud/missing")
+		Expect(errors.As(err, &notFound)).To(BeTrue())
+	})
+
+	It("returns the team relationships of a service across pages", func() {
+		server.PageSize = 2
+		svc := server.AddService(fake.Service{Name: "api", TierLevel: 1})
+		var relIDs []string
+		for _, name := range []string{"a", "b", "c"} {
+			relID, err := c.CreateOpsgenieTeamRelationship(ctx, svc.ID, server.AddTeam(name))
+			Expect(err).NotTo(HaveOccurred())
+			relIDs = append(relIDs, relID)
+		}
+
+		found, err := c.GetServiceByID(ctx, svc.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found.TeamRelationships).To(HaveLen(3))
+		Expect(found.TeamRelationships[2].ID).To(Equal(relIDs[2]))
+	})
+
+	It("pages through teams", func() {
+		server.PageSize = 2
+		for _, name := range []string{"a", "b", "c", "d", "e"} {
+			server.AddTeam(name)
+		}
+
+		id, err := c.GetOpsgenieTeamIDByName(ctx, "e")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).NotTo(BeEmpty())
+		Expect(server.Calls("opsgenie")).To(Equal(3))
+	})
+
+	It("manages teams through the REST API", func() {
+		created, err := c.CreateOpsgenieTeam(ctx, &jsmclient.OpsgenieTeamRequest{
+			Name:        "SRE",
+			Description: "site reliability",
+			Members: []jsmclient.TeamMember{
+				{Username: "ada@example.com", Role: jsmclient.TeamMemberRoleAdmin},
+				{Username: "bob@example.com", Role: jsmclient.TeamMemberRoleUser},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created.ID).To(HavePrefix("ari:cloud:opsgenie::team/cloud/"))
+
+		id, err := c.GetOpsgenieTeamIDByName(ctx, "SRE")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(Equal(created.ID))
+
+		_, err = c.CreateOpsgenieTeam(ctx, &jsmclient.OpsgenieTeamRequest{Name: "SRE"})
+		var exists *jsmclient.AlreadyExistsError
+		Expect(errors.As(err, &exists)).To(BeTrue())
+
+		Expect(c.UpdateOpsgenieTeam(ctx, created.ID, &jsmclient.OpsgenieTeamRequest{
+			Name:    "Platform",
+			Members: []jsmclient.TeamMember{{Username: "bob@example.com", Role: jsmclient.TeamMemberRoleAdmin}},
+		})).To(Succeed())
+		team, err := c.GetOpsgenieTeam(ctx, created.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(team).To(Equal(&jsmclient.OpsgenieTeam{
+			ID:      created.ID,
+			Name:    "Platform",
+			Members: []jsmclient.TeamMember{{Username: "bob@example.com", Role: jsmclient.TeamMemberRoleAdmin}},
+		}))
+		id, err = c.GetOpsgenieTeamIDByName(ctx, "Platform")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(Equal(created.ID))
+
+		Expect(c.UpdateOpsgenieTeam(ctx, created.ID, &jsmclient.OpsgenieTeamRequest{Name: "Platform", Description: "kept members"})).To(Succeed())
+		team, err = c.GetOpsgenieTeam(ctx, created.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(team.Members).To(Equal([]jsmclient.TeamMember{{Username: "bob@example.com", Role: jsmclient.TeamMemberRoleAdmin}}))
+
+		Expect(c.UpdateOpsgenieTeam(ctx, created.ID, &jsmclient.OpsgenieTeamRequest{Name: "Platform", Members: []jsmclient.TeamMember{}})).To(Succeed())
+		team, err = c.GetOpsgenieTeam(ctx, created.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(team.Members).To(BeEmpty())
+
+		err = c.UpdateOpsgenieTeam(ctx, created.ID, &jsmclient.OpsgenieTeamRequest{
+			Name:    "Platform",
+			Members: []jsmclient.TeamMember{{Username: "bob@example.com", Role: "owner"}},
+		})
+		var validation *jsmclient.ValidationError
+		Expect(errors.As(err, &validation)).To(BeTrue())
+
+		Expect(c.DeleteOpsgenieTeam(ctx, created.ID)).To(Succeed())
+		_, ok := server.Team(created.ID)
+		Expect(ok).To(BeFalse())
+
+		_, err = c.GetOpsgenieTeam(ctx, created.ID)
+		var notFound *jsmclient.NotFoundError
+		Expect(errors.As(err, &notFound)).To(BeTrue())
+		Expect(server.Calls("getTeam")).To(Equal(4))
+	})
+
+	It("fails injected calls", func() {
+		server.FailNext("devOpsServices", http.StatusInternalServerError, "boom")
+
+		_, err := c.GetServiceByName(ctx, "api")
+		var serverErr *jsmclient.ServerError
+		Expect(errors.As(err, &serverErr)).To(BeTrue())
+
+		_, err = c.GetServiceByName(ctx, "api")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("applies a request whose response gets lost", func() {
+		server.LoseNextResponse("createDevOpsService")
+
+		_, err := c.CreateService(ctx, &jsmclient.CreateServiceRequest{Name: "lost", TierLevel: 2})
+		var serverErr *jsmclient.ServerError
+		Expect(errors.As(err, &serverErr)).To(BeTrue())
+
+		found, err := c.GetServiceByName(ctx, "lost")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).NotTo(BeNil())
+	})
+})
@@ -0,0 +1,182 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// Defaults for HTTPConfig.
+const (
+	DefaultRequestTimeout      = 30 * time.Second
+	DefaultDialTimeout         = 10 * time.Second
+	DefaultTLSHandshakeTimeout = 10 * time.Second
+	DefaultIdleConnTimeout     = 90 * time.Second
+	DefaultMaxIdleConns        = 100
+	// DefaultMaxIdleConnsPerHost is well above the net/http default of 2, as
+	// all requests of the operator go to the same Atlassian host.
+	DefaultMaxIdleConnsPerHost = 20
+)
+
+// HTTPConfig configures the connections used by both the GraphQL and the REST
+// client. Zero values mean the defaults above.
+type HTTPConfig struct {
+	// RequestTimeout bounds a single attempt of a request, including reading
+	// the response body. Waiting for the rate limit budget and retries don't
+	// count against it.
+	RequestTimeout      time.Duration
+	DialTimeout         time.Duration
+	TLSHandshakeTimeout time.Duration
+
+	// ProxyURL is the HTTP(S) proxy to use. When empty the HTTPS_PROXY,
+	// HTTP_PROXY and NO_PROXY environment variables apply.
+	ProxyURL string
+
+	// CAFile and CAData are PEM encoded CA certificates trusted in addition
+	// to the system pool, e.g. of a TLS intercepting proxy.
+	CAFile string
+	CAData []byte
+
+	// CertFile and KeyFile, or CertData and KeyData, are a PEM encoded client
+	// certificate and key presented for mutual TLS.
+	CertFile string
+	KeyFile  string
+	CertData []byte
+	KeyData  []byte
+
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	MaxConnsPerHost     int
+	IdleConnTimeout     time.Duration
+}
+
+// newHTTPTransport builds the transport every request to Atlassian goes
+// through, wrapped so that each attempt is bounded by the request timeout.
+func newHTTPTransport(cfg HTTPConfig) (http.RoundTripper, error) {
+	proxy := http.ProxyFromEnvironment
+	if cfg.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	tlsConfig, err := newTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   orDefault(cfg.DialTimeout, DefaultDialTimeout),
+		KeepAlive: 30 * time.Second,
+	}
+	transport := &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialer.DialContext,
+		TLSClientConfig:       tlsConfig,
+		TLSHandshakeTimeout:   orDefault(cfg.TLSHandshakeTimeout, DefaultTLSHandshakeTimeout),
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          orDefault(cfg.MaxIdleConns, DefaultMaxIdleConns),
+		MaxIdleConnsPerHost:   orDefault(cfg.MaxIdleConnsPerHost, DefaultMaxIdleConnsPerHost),
+		MaxConnsPerHost:       cfg.MaxConnsPerHost,
+		IdleConnTimeout:       orDefault(cfg.IdleConnTimeout, DefaultIdleConnTimeout),
+		ExpectContinueTimeout: time.Second,
+	}
+
+	return &timeoutTransport{
+		base:    transport,
+		timeout: orDefault(cfg.RequestTimeout, DefaultRequestTimeout),
+	}, nil
+}
+
+// newTLSConfig adds the extra CA certificates and the client certificate to
+// the default TLS configuration.
+func newTLSConfig(cfg HTTPConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	caData := cfg.CAData
+	if cfg.CAFile != "" {
+		data, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA file: %w", err)
+		}
+		caData = append(append(caData, '\n'), data...)
+	}
+	if len(caData) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caData) {
+			return nil, errors.New("no valid PEM certificates found in the CA bundle")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	certData, keyData := cfg.CertData, cfg.KeyData
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		var err error
+		if certData, err = os.ReadFile(cfg.CertFile); err != nil {
+			return nil, fmt.Errorf("reading client certificate: %w", err)
+		}
+		if keyData, err = os.ReadFile(cfg.KeyFile); err != nil {
+			return nil, fmt.Errorf("reading client key: %w", err)
+		}
+	}
+	if len(certData) > 0 || len(keyData) > 0 {
+		cert, err := tls.X509KeyPair(certData, keyData)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+func orDefault[T comparable](value, def T) T {
+	var zero T
+	if value == zero {
+		return def
+	}
+	return value
+}
+
+// timeoutTransport bounds every round trip, including reading the body, so
+// a hung connection can't block a reconcile worker forever.
+type timeoutTransport struct {
+	base    http.RoundTripper
+	timeout time.Duration
+}
+
+func (t *timeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// the deadline has to outlive RoundTrip until the body is consumed
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// selfSignedCert returns a PEM encoded self-signed client certificate and key.
+func selfSignedCert() (certPEM, keyPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "jsm-operator"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	Expect(err).NotTo(HaveOccurred())
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+var _ = Describe("HTTP configuration", func() {
+	newClient := func(url string, cfg HTTPConfig) (*JSMClient, error) {
+		return NewJSMClient(JSMConfig{
+			GraphQLURL: url,
+			RestURL:    url,
+			Token:      "token",
+			Username:   "user",
+			CloudID:    "cloud",
+			HTTP:       cfg,
+		})
+	}
+
+	lookup := func(c *JSMClient) error {
+		_, err := c.GetServiceByName(context.Background(), "api")
+		return err
+	}
+
+	It("trusts extra CA certificates from data or a file", func() {
+		server := httptest.NewTLSServer(servicePages([][]string{{}}))
+		defer server.Close()
+		caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+		c, err := newClient(server.URL, HTTPConfig{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lookup(c)).NotTo(Succeed())
+
+		c, err = newClient(server.URL, HTTPConfig{CAData: caPEM})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lookup(c)).To(Succeed())
+
+		caFile := filepath.Join(GinkgoT().TempDir(), "ca.crt")
+		Expect(os.WriteFile(caFile, caPEM, 0o600)).To(Succeed())
+		c, err = newClient(server.URL, HTTPConfig{CAFile: caFile})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lookup(c)).To(Succeed())
+
+		_, err = newClient(server.URL, HTTPConfig{CAData: []byte("not a certificate")})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("presents a client certificate for mutual TLS", func() {
+		certPEM, keyPEM := selfSignedCert()
+		clientCAs := x509.NewCertPool()
+		Expect(clientCAs.AppendCertsFromPEM(certPEM)).To(BeTrue())
+
+		server := httptest.NewUnstartedServer(servicePages([][]string{{}}))
+		server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+		server.StartTLS()
+		defer server.Close()
+		caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+		c, err := newClient(server.URL, HTTPConfig{CAData: caPEM})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lookup(c)).NotTo(Succeed())
+
+		c, err = newClient(server.URL, HTTPConfig{CAData: caPEM, CertData: certPEM, KeyData: keyPEM})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lookup(c)).To(Succeed())
+	})
+
+	It("sends requests through the configured proxy", func() {
+		var proxied atomic.Int32
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			proxied.Add(1)
+			Expect(r.URL.Host).To(Equal("jsm.invalid"))
+			servicePages([][]string{{}})(w, r)
+		}))
+		defer proxy.Close()
+
+		c, err := newClient("http://jsm.invalid/graphql", HTTPConfig{ProxyURL: proxy.URL})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lookup(c)).To(Succeed())
+		Expect(proxied.Load()).To(BeEquivalentTo(1))
+	})
+
+	It("gives up on a hung connection after the request timeout", func() {
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer server.Close()
+		defer close(release)
+
+		c, err := newClient(server.URL, HTTPConfig{RequestTimeout: 50 * time.Millisecond})
+		Expect(err).NotTo(HaveOccurred())
+
+		start := time.Now()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_, err = c.GetServiceByName(ctx, "api")
+		Expect(err).To(HaveOccurred())
+		Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+	})
+})
@@ -7,14 +7,12 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	jira "github.com/andygrunwald/go-jira"
 	"github.com/hasura/go-graphql-client"
 )
 
-// error message conflict
-const ErrRevisionConflict = "Specified revision was incorrect"
-
 type Service struct {
 	ID              string  `json:"id"`
 	Name            string  `json:"name"`
@@ -23,6 +21,19 @@ type Service struct {
 	TierLevel       int     `json:"tierLevel"`
 	ApplicationType string  `json:"applicationType,omitempty"` // Optional, e.g., "APPLICATIONS", "BUSINESS_SERVICES"
 	TierID          string  `json:"tierId,omitempty"`
+	TierName        string  `json:"tierName,omitempty"`
+	// ResponderTeams are the team ARIs of the "responders" property. Only
+	// filled in by GetServiceByID.
+	ResponderTeams []string `json:"responderTeams,omitempty"`
+	// TeamRelationships are the Opsgenie teams linked with the service. Only
+	// filled in by GetServiceByID.
+	TeamRelationships []TeamRelationship `json:"teamRelationships,omitempty"`
+}
+
+// TeamRelationship links a service with an Opsgenie team.
+type TeamRelationship struct {
+	ID     string `json:"id"`
+	TeamID string `json:"teamId"`
 }
 
 type CreateServiceRequest struct {
@@ -38,14 +49,41 @@ type JSMClient struct {
 	GraphQLClient *graphql.Client
 	JiraClient    *jira.Client
 	CloudID       string
+	// CaseInsensitiveNames makes name lookups ignore letter case.
+	CaseInsensitiveNames bool
+	// Teams is the cached Opsgenie team directory. It is shared by everything
+	// that uses this client, so all reconcilers resolve teams from one index.
+	Teams *TeamDirectory
+	// Tiers is the cached service tier catalog.
+	Tiers *TierCatalog
 }
 
 type JSMConfig struct {
 	GraphQLURL string
 	RestURL    string
-	Token      string
-	Username   string
-	CloudID    string
+	// AuthMode selects the credentials below, defaults to AuthModeBasic.
+	AuthMode AuthMode
+	// Token is the API token for basic auth or the bearer token.
+	Token string
+	// Username is only used for basic auth.
+	Username string
+	// OAuth2 is only used with AuthModeOAuth2.
+	OAuth2  OAuth2Config
+	CloudID string
+	// CaseInsensitiveNames makes name lookups ignore letter case.
+	CaseInsensitiveNames bool
+	// TeamCacheTTL is how long the Opsgenie team directory is kept before it
+	// is fetched again. Defaults to DefaultTeamCacheTTL.
+	TeamCacheTTL time.Duration
+	// TierCacheTTL is how long the service tier catalog is kept before it is
+	// fetched again. Defaults to DefaultTierCacheTTL.
+	TierCacheTTL time.Duration
+	// RateLimit configures the request budget and retries shared by the
+	// GraphQL and the REST client.
+	RateLimit RateLimitConfig
+	// HTTP configures timeouts, proxy, TLS and connection pooling of both
+	// clients.
+	HTTP HTTPConfig
 }
 
 type CreateDevOpsServiceInput struct {
@@ -94,86 +132,266 @@ type updateResponderValue struct {
 	Teams []string `json:"teams"`
 }
 
+// responders returns the team ARIs of the responders property. A service
+// without responders is sent an empty list rather than null, so removing the
+// last team clears them.
+func responders(teamARNs []string) []string {
+	if teamARNs == nil {
+		return []string{}
+	}
+	return teamARNs
+}
+
 func NewJSMClient(config JSMConfig) (*JSMClient, error) {
-	if config.GraphQLURL == "" || config.RestURL == "" || config.Token == "" || config.Username == "" || config.CloudID == "" {
+	if config.GraphQLURL == "" || config.RestURL == "" || config.CloudID == "" {
 		return nil, errors.New("invalid JSM configuration: all fields must be provided")
 	}
+	if err := validateAuth(config); err != nil {
+		return nil, err
+	}
 
-	tp := jira.BasicAuthTransport{
-		Username: config.Username,
-		Password: config.Token,
+	base, err := newHTTPTransport(config.HTTP)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSM HTTP configuration: %w", err)
 	}
+	transport := &metricsTransport{base: newAuthTransport(config,
+		&failedResponseTransport{base: newRateLimitTransport(base, config.RateLimit)},
+		base,
+	)}
+	httpClient := &http.Client{Transport: transport}
 
 	if !strings.HasSuffix(config.RestURL, "/") {
 		config.RestURL += "/"
 	}
 
-	jiraClient, err := jira.NewClient(tp.Client(), fmt.Sprintf("%s/v1/", config.RestURL))
+	jiraClient, err := jira.NewClient(httpClient, config.RestURL+"v1/")
 	if err != nil {
 		return nil, err
 	}
 
-	graphqlClient := graphql.NewClient(config.GraphQLURL, http.DefaultClient).WithRequestModifier(func(req *http.Request) {
-		req.Header.Set("Authorization", "Basic "+basicAuth(config.Username, config.Token))
-	})
+	graphqlClient := graphql.NewClient(config.GraphQLURL, httpClient)
 
-	return &JSMClient{
-		GraphQLClient: graphqlClient,
-		JiraClient:    jiraClient,
-		CloudID:       config.CloudID,
-	}, nil
+	c := &JSMClient{
+		GraphQLClient:        graphqlClient,
+		JiraClient:           jiraClient,
+		CloudID:              config.CloudID,
+		CaseInsensitiveNames: config.CaseInsensitiveNames,
+	}
+	c.Teams = NewTeamDirectory(c, config.TeamCacheTTL)
+	c.Tiers = NewTierCatalog(c, config.TierCacheTTL)
+
+	return c, nil
 }
 
 func basicAuth(username, password string) string {
 	return base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
 }
 
-// GetServiceByName retrieves a JSM service by its name.
+// servicePageSize is the number of services requested per page when walking
+// the devOpsServices connection.
+const servicePageSize = 100
+
+// GetServiceByName retrieves a JSM service by its exact name.
 // It's a graphql api, since JSM services are not available via the REST API.
+// The API only supports a "name contains" filter, so every page of the
+// connection is fetched and the results are narrowed down to exact matches
+// client-side. It returns nil if no service matches and an
+// *AmbiguousNameError if more than one does.
 func (c *JSMClient) GetServiceByName(ctx context.Context, name string) (*Service, error) {
-	var query struct {
-		DevOpsServices struct {
-			Edges []struct {
-				Node struct {
-					ID       string
-					Name     string
-					Revision string
+	var matches []*Service
+	var after *graphql.String
+	for {
+		var query struct {
+			DevOpsServices struct {
+				Edges []struct {
+					Node struct {
+						ID       string
+						Name     string
+						Revision string
+					}
 				}
+				PageInfo struct {
+					HasNextPage bool
+					EndCursor   string
+				}
+			} `graphql:"devOpsServices(cloudId: $cloudId, first: $first, after: $after, filter: { nameContains: $name })"`
+		}
+
+		variables := map[string]any{
+			"cloudId": graphql.String(c.CloudID),
+			"name":    graphql.String(name),
+			"first":   graphql.Int(servicePageSize),
+			"after":   after,
+		}
+		if err := c.query(ctx, "GetServiceByName", &query, variables); err != nil {
+			return nil, err
+		}
+
+		for _, edge := range query.DevOpsServices.Edges {
+			if !c.namesMatch(edge.Node.Name, name) {
+				continue
 			}
-		} `graphql:"devOpsServices(cloudId: $cloudId, filter: { nameContains: $name })"`
+			matches = append(matches, &Service{
+				ID:       edge.Node.ID,
+				Name:     edge.Node.Name,
+				Revision: edge.Node.Revision,
+			})
+		}
+
+		pageInfo := query.DevOpsServices.PageInfo
+		if !pageInfo.HasNextPage || pageInfo.EndCursor == "" {
+			break
+		}
+		cursor := graphql.String(pageInfo.EndCursor)
+		after = &cursor
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+		return matches[0], nil
+	default:
+		ids := make([]string, 0, len(matches))
+		for _, m := range matches {
+			ids = append(ids, m.ID)
+		}
+		return nil, &AmbiguousNameError{Kind: "service", Name: name, IDs: ids}
+	}
+}
+
+// relationshipPageSize is the number of team relationships requested per
+// page. A service is usually linked with a handful of teams, so the first page
+// requested with the service mostly holds all of them.
+const relationshipPageSize = 50
+
+// GetServiceByID retrieves the full state of a JSM service by its ARI: name,
+// description, tier, service type, responders and the linked Opsgenie teams.
+// It returns a *NotFoundError if the service does not exist.
+func (c *JSMClient) GetServiceByID(ctx context.Context, id string) (*Service, error) {
+	var query struct {
+		DevOpsService *struct {
+			ID          string  `json:"id"`
+			Name        string  `json:"name"`
+			Description *string `json:"description"`
+			Revision    string  `json:"revision"`
+			ServiceTier struct {
+				ID    string `json:"id"`
+				Level int    `json:"level"`
+				Name  string `json:"name"`
+			} `json:"serviceTier"`
+			ServiceType struct {
+				Key string `json:"key"`
+			} `json:"serviceType"`
+			Properties []struct {
+				Key   string `json:"key"`
+				Value struct {
+					Teams []string `json:"teams"`
+				} `json:"value"`
+			} `json:"properties"`
+			OpsgenieTeamRelationships struct {
+				Edges []struct {
+					Node struct {
+						ID             string `json:"id"`
+						OpsgenieTeamID string `json:"opsgenieTeamId"`
+					} `json:"node"`
+				} `json:"edges"`
+				PageInfo struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+			} `graphql:"opsgenieTeamRelationships(first: $first)"`
+		} `graphql:"devOpsService(id: $id)"`
 	}
 
 	variables := map[string]any{
-		"cloudId": graphql.String(c.CloudID),
-		"name":    graphql.String(name),
+		"id":    graphql.ID(id),
+		"first": graphql.Int(relationshipPageSize),
 	}
-	err := c.GraphQLClient.Query(ctx, &query, variables, graphql.OperationName("GetServiceByName"))
 
-	if err != nil {
+	if err := c.query(ctx, "GetServiceByID", &query, variables); err != nil {
 		return nil, err
 	}
 
-	if len(query.DevOpsServices.Edges) == 0 {
-		return nil, nil
+	node := query.DevOpsService
+	if node == nil {
+		return nil, &NotFoundError{Message: fmt.Sprintf("service %s not found", id)}
 	}
 
-	// we expect only one service with the given name, so we can safely return the first one
-	serviceNode := query.DevOpsServices.Edges[0].Node
-	return &Service{
-		ID:       serviceNode.ID,
-		Name:     serviceNode.Name,
-		Revision: serviceNode.Revision,
-	}, nil
+	svc := &Service{
+		ID:              node.ID,
+		Name:            node.Name,
+		Description:     node.Description,
+		Revision:        node.Revision,
+		TierID:          node.ServiceTier.ID,
+		TierLevel:       node.ServiceTier.Level,
+		TierName:        node.ServiceTier.Name,
+		ApplicationType: node.ServiceType.Key,
+	}
+	for _, prop := range node.Properties {
+		if prop.Key == "responders" {
+			svc.ResponderTeams = append(svc.ResponderTeams, prop.Value.Teams...)
+		}
+	}
+	connection := node.OpsgenieTeamRelationships
+	for _, edge := range connection.Edges {
+		svc.TeamRelationships = append(svc.TeamRelationships, TeamRelationship{
+			ID:     edge.Node.ID,
+			TeamID: edge.Node.OpsgenieTeamID,
+		})
+	}
+	if connection.PageInfo.HasNextPage && connection.PageInfo.EndCursor != "" {
+		cursor := graphql.String(connection.PageInfo.EndCursor)
+		rest, err := c.listOpsgenieTeamRelationships(ctx, id, &cursor)
+		if err != nil {
+			return nil, err
+		}
+		svc.TeamRelationships = append(svc.TeamRelationships, rest...)
+	}
+	return svc, nil
+}
+
+// namesMatch compares a remote object name with the requested one, honouring
+// the client's case sensitivity setting.
+func (c *JSMClient) namesMatch(remote, wanted string) bool {
+	if c.CaseInsensitiveNames {
+		return strings.EqualFold(remote, wanted)
+	}
+	return remote == wanted
+}
+
+// query sends a GraphQL query named op and records it in the API metrics.
+func (c *JSMClient) query(ctx context.Context, op string, q any, variables map[string]any) (err error) {
+	ctx, done := instrument(ctx, op)
+	defer func() { done(err) }()
+	ctx, failed := withFailedResponse(ctx)
+
+	if err := c.GraphQLClient.Query(ctx, q, variables, graphql.OperationName(op)); err != nil {
+		return classifyError(op, failed.typed(err))
+	}
+	return nil
+}
+
+// mutate sends a GraphQL mutation named op and records it in the API metrics.
+// check turns an unsuccessful payload into a typed error, so that conflicts
+// reported in the payload are counted as such.
+func (c *JSMClient) mutate(ctx context.Context, op string, m any, variables map[string]any, check func() error) (err error) {
+	ctx, done := instrument(ctx, op)
+	defer func() { done(err) }()
+	ctx, failed := withFailedResponse(ctx)
+
+	if err := c.GraphQLClient.Mutate(ctx, m, variables, graphql.OperationName(op)); err != nil {
+		return classifyError(op, failed.typed(err))
+	}
+	return check()
 }
 
 // CreateService creates a new JSM service with the given specifications.
 func (c *JSMClient) CreateService(ctx context.Context, req *CreateServiceRequest) (*Service, error) {
 	var mutation struct {
 		CreateDevOpsService struct {
-			Success bool `json:"success"`
-			Errors  []struct {
-				Message string `json:"message"`
-			} `json:"errors"`
+			Success bool            `json:"success"`
+			Errors  []mutationError `json:"errors"`
 			Service struct {
 				ID          string `json:"id"`
 				Name        string `json:"name"`
@@ -205,7 +423,7 @@ func (c *JSMClient) CreateService(ctx context.Context, req *CreateServiceRequest
 			Value: struct {
 				Teams []string `json:"teams"`
 			}{
-				Teams: req.TeamARNs,
+				Teams: responders(req.TeamARNs),
 			},
 		},
 	}
@@ -215,17 +433,14 @@ func (c *JSMClient) CreateService(ctx context.Context, req *CreateServiceRequest
 		"input": input,
 	}
 
-	err := c.GraphQLClient.Mutate(ctx, &mutation, variables, graphql.OperationName("CreateDevOpsService"))
-	if err != nil {
-		return nil, fmt.Errorf("mutation failed: %w", err)
-	}
-
-	if !mutation.CreateDevOpsService.Success {
-		var messages []string
-		for _, e := range mutation.CreateDevOpsService.Errors {
-			messages = append(messages, e.Message)
+	err := c.mutate(ctx, "CreateDevOpsService", &mutation, variables, func() error {
+		if !mutation.CreateDevOpsService.Success {
+			return payloadError("CreateDevOpsService", mutation.CreateDevOpsService.Errors)
 		}
-		return nil, fmt.Errorf("jsm service creation failed: %s", strings.Join(messages, "; "))
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	svc := mutation.CreateDevOpsService.Service
@@ -238,11 +453,40 @@ func (c *JSMClient) CreateService(ctx context.Context, req *CreateServiceRequest
 	}, nil
 }
 
+// GetTierIDByLevel resolves the ID of the service tier with the given level
+// using the client's tier catalog.
 func (c *JSMClient) GetTierIDByLevel(ctx context.Context, level int) (string, error) {
+	tier, err := c.Tiers.ByLevel(ctx, level)
+	if err != nil {
+		return "", err
+	}
+	return tier.ID, nil
+}
+
+// GetTierByLevel returns the service tier with the given level.
+func (c *JSMClient) GetTierByLevel(ctx context.Context, level int) (*Tier, error) {
+	return c.Tiers.ByLevel(ctx, level)
+}
+
+// GetTierByName returns the service tier with the given name, e.g. "Critical".
+func (c *JSMClient) GetTierByName(ctx context.Context, name string) (*Tier, error) {
+	return c.Tiers.ByName(ctx, name)
+}
+
+// InvalidateTierCache drops the cached tier catalog, the next tier lookup
+// fetches all tiers again.
+func (c *JSMClient) InvalidateTierCache() {
+	c.Tiers.Invalidate()
+}
+
+// ListServiceTiers returns every service tier of the cloud site.
+func (c *JSMClient) ListServiceTiers(ctx context.Context) ([]Tier, error) {
 	var query struct {
 		DevOpsServiceTiers []struct {
-			ID    string `json:"id"`
-			Level int    `json:"level"`
+			ID          string `json:"id"`
+			Level       int    `json:"level"`
+			Name        string `json:"name"`
+			Description string `json:"description"`
 		} `graphql:"devOpsServiceTiers(cloudId: $cloudId)"`
 	}
 
@@ -250,28 +494,28 @@ func (c *JSMClient) GetTierIDByLevel(ctx context.Context, level int) (string, er
 		"cloudId": graphql.String(c.CloudID),
 	}
 
-	err := c.GraphQLClient.Query(ctx, &query, variables, graphql.OperationName("GetTierIDByLevel"))
-	if err != nil {
-		return "", fmt.Errorf("failed to query service tiers: %w", err)
+	if err := c.query(ctx, "ListServiceTiers", &query, variables); err != nil {
+		return nil, err
 	}
 
+	tiers := make([]Tier, 0, len(query.DevOpsServiceTiers))
 	for _, tier := range query.DevOpsServiceTiers {
-		if tier.Level == level {
-			return tier.ID, nil
-		}
+		tiers = append(tiers, Tier{
+			ID:          tier.ID,
+			Level:       tier.Level,
+			Name:        tier.Name,
+			Description: tier.Description,
+		})
 	}
-
-	return "", fmt.Errorf("no service tier found for level %d", level)
+	return tiers, nil
 }
 
 // UpdateService updates an existing JSM service with the given specifications.
 func (c *JSMClient) UpdateService(ctx context.Context, req *UpdateServiceRequest) (*Service, error) {
 	var mutation struct {
 		UpdateDevOpsService struct {
-			Success bool `json:"success"`
-			Errors  []struct {
-				Message string `json:"message"`
-			} `json:"errors"`
+			Success bool            `json:"success"`
+			Errors  []mutationError `json:"errors"`
 			Service struct {
 				ID          string `json:"id"`
 				Name        string `json:"name"`
@@ -297,7 +541,7 @@ func (c *JSMClient) UpdateService(ctx context.Context, req *UpdateServiceRequest
 			{
 				Key: "responders",
 				Value: updateResponderValue{
-					Teams: req.TeamARNs,
+					Teams: responders(req.TeamARNs),
 				},
 			},
 		},
@@ -307,17 +551,14 @@ func (c *JSMClient) UpdateService(ctx context.Context, req *UpdateServiceRequest
 		"input": input,
 	}
 
-	err := c.GraphQLClient.Mutate(ctx, &mutation, variables, graphql.OperationName("UpdateDevOpsService"))
-	if err != nil {
-		return nil, fmt.Errorf("mutation failed: %w", err)
-	}
-
-	if !mutation.UpdateDevOpsService.Success {
-		var messages []string
-		for _, e := range mutation.UpdateDevOpsService.Errors {
-			messages = append(messages, e.Message)
+	err := c.mutate(ctx, "UpdateDevOpsService", &mutation, variables, func() error {
+		if !mutation.UpdateDevOpsService.Success {
+			return payloadError("UpdateDevOpsService", mutation.UpdateDevOpsService.Errors)
 		}
-		return nil, fmt.Errorf("jsm service update failed: %s", strings.Join(messages, "; "))
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	svc := mutation.UpdateDevOpsService.Service
@@ -331,17 +572,33 @@ func (c *JSMClient) UpdateService(ctx context.Context, req *UpdateServiceRequest
 	}, nil
 }
 
-func (c *JSMClient) IsRevisionConflict(err error) bool {
-	return strings.Contains(err.Error(), ErrRevisionConflict)
+// DeleteService deletes the JSM service with the given ARI. JSM removes the
+// relationships of the service together with it.
+func (c *JSMClient) DeleteService(ctx context.Context, id string) error {
+	var mutation struct {
+		DeleteDevOpsService struct {
+			Success bool            `json:"success"`
+			Errors  []mutationError `json:"errors"`
+		} `graphql:"deleteDevOpsService(input: {id: $id})"`
+	}
+
+	variables := map[string]any{
+		"id": graphql.ID(id),
+	}
+
+	return c.mutate(ctx, "DeleteDevOpsService", &mutation, variables, func() error {
+		if !mutation.DeleteDevOpsService.Success {
+			return payloadError("DeleteDevOpsService", mutation.DeleteDevOpsService.Errors)
+		}
+		return nil
+	})
 }
 
 func (c *JSMClient) CreateOpsgenieTeamRelationship(ctx context.Context, serviceID, teamID string) (string, error) {
 	var mutation struct {
 		CreateDevOpsServiceAndOpsgenieTeamRelationship struct {
-			Success bool `json:"success"`
-			Errors  []struct {
-				Message string `json:"message"`
-			} `json:"errors"`
+			Success                            bool            `json:"success"`
+			Errors                             []mutationError `json:"errors"`
 			ServiceAndOpsgenieTeamRelationship struct {
 				ID string `json:"id"`
 			} `json:"serviceAndOpsgenieTeamRelationship"`
@@ -354,50 +611,162 @@ func (c *JSMClient) CreateOpsgenieTeamRelationship(ctx context.Context, serviceI
 		"teamId":    graphql.ID(teamID),
 	}
 
-	err := c.GraphQLClient.Mutate(ctx, &mutation, variables, graphql.OperationName("CreateDevOpsServiceAndOpsgenieTeamRelationship"))
+	err := c.mutate(ctx, "CreateDevOpsServiceAndOpsgenieTeamRelationship", &mutation, variables, func() error {
+		if !mutation.CreateDevOpsServiceAndOpsgenieTeamRelationship.Success {
+			return payloadError("CreateDevOpsServiceAndOpsgenieTeamRelationship", mutation.CreateDevOpsServiceAndOpsgenieTeamRelationship.Errors)
+		}
+		return nil
+	})
 	if err != nil {
-		return "", fmt.Errorf("relationship mutation failed: %w", err)
+		return "", err
 	}
 
-	if !mutation.CreateDevOpsServiceAndOpsgenieTeamRelationship.Success {
-		var messages []string
-		for _, e := range mutation.CreateDevOpsServiceAndOpsgenieTeamRelationship.Errors {
-			messages = append(messages, e.Message)
+	return mutation.CreateDevOpsServiceAndOpsgenieTeamRelationship.ServiceAndOpsgenieTeamRelationship.ID, nil
+}
+
+// ListOpsgenieTeamRelationships returns every Opsgenie team relationship of
+// the service with the given ARI, following the connection cursor across all
+// pages. It returns a *NotFoundError if the service does not exist.
+func (c *JSMClient) ListOpsgenieTeamRelationships(ctx context.Context, serviceID string) ([]TeamRelationship, error) {
+	return c.listOpsgenieTeamRelationships(ctx, serviceID, nil)
+}
+
+// listOpsgenieTeamRelationships returns the Opsgenie team relationships of
+// the service with the given ARI from the cursor after on.
+func (c *JSMClient) listOpsgenieTeamRelationships(ctx context.Context, serviceID string, after *graphql.String) ([]TeamRelationship, error) {
+	var relationships []TeamRelationship
+	for {
+		var query struct {
+			DevOpsService *struct {
+				OpsgenieTeamRelationships struct {
+					Edges []struct {
+						Node struct {
+							ID             string `json:"id"`
+							OpsgenieTeamID string `json:"opsgenieTeamId"`
+						} `json:"node"`
+					} `json:"edges"`
+					PageInfo struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+				} `graphql:"opsgenieTeamRelationships(first: $first, after: $after)"`
+			} `graphql:"devOpsService(id: $id)"`
 		}
-		return "", fmt.Errorf("failed to create relationship: %s", strings.Join(messages, "; "))
+
+		variables := map[string]any{
+			"id":    graphql.ID(serviceID),
+			"first": graphql.Int(relationshipPageSize),
+			"after": after,
+		}
+
+		if err := c.query(ctx, "ListOpsgenieTeamRelationships", &query, variables); err != nil {
+			return nil, err
+		}
+		if query.DevOpsService == nil {
+			return nil, &NotFoundError{Message: fmt.Sprintf("service %s not found", serviceID)}
+		}
+
+		connection := query.DevOpsService.OpsgenieTeamRelationships
+		for _, edge := range connection.Edges {
+			relationships = append(relationships, TeamRelationship{ID: edge.Node.ID, TeamID: edge.Node.OpsgenieTeamID})
+		}
+
+		if !connection.PageInfo.HasNextPage || connection.PageInfo.EndCursor == "" {
+			break
+		}
+		cursor := graphql.String(connection.PageInfo.EndCursor)
+		after = &cursor
 	}
 
-	return mutation.CreateDevOpsServiceAndOpsgenieTeamRelationship.ServiceAndOpsgenieTeamRelationship.ID, nil
+	return relationships, nil
 }
 
-func (c *JSMClient) GetOpsgenieTeamIDByName(ctx context.Context, name string) (string, error) {
-	var query struct {
-		Opsgenie struct {
-			AllOpsgenieTeams struct {
-				Edges []struct {
-					Node struct {
-						ID   string `json:"id"`
-						Name string `json:"name"`
-					} `json:"node"`
-				} `json:"edges"`
-			} `graphql:"allOpsgenieTeams(cloudId: $cloudId)"`
-		} `graphql:"opsgenie"`
+// DeleteOpsgenieTeamRelationship removes the link between a service and an
+// Opsgenie team.
+func (c *JSMClient) DeleteOpsgenieTeamRelationship(ctx context.Context, relationshipID string) error {
+	var mutation struct {
+		DeleteDevOpsServiceAndOpsgenieTeamRelationship struct {
+			Success bool            `json:"success"`
+			Errors  []mutationError `json:"errors"`
+		} `graphql:"deleteDevOpsServiceAndOpsgenieTeamRelationship(input: {id: $id})"`
 	}
 
 	variables := map[string]any{
-		"cloudId": graphql.ID(c.CloudID),
+		"id": graphql.ID(relationshipID),
 	}
 
-	err := c.GraphQLClient.Query(ctx, &query, variables, graphql.OperationName("ResolveOpsgenieTeamIDByName"))
+	return c.mutate(ctx, "DeleteDevOpsServiceAndOpsgenieTeamRelationship", &mutation, variables, func() error {
+		if !mutation.DeleteDevOpsServiceAndOpsgenieTeamRelationship.Success {
+			return payloadError("DeleteDevOpsServiceAndOpsgenieTeamRelationship", mutation.DeleteDevOpsServiceAndOpsgenieTeamRelationship.Errors)
+		}
+		return nil
+	})
+}
+
+// GetOpsgenieTeamIDByName resolves the ARI of an Opsgenie team by its name
+// using the client's team directory.
+func (c *JSMClient) GetOpsgenieTeamIDByName(ctx context.Context, name string) (string, error) {
+	team, err := c.Teams.Lookup(ctx, name)
 	if err != nil {
-		return "", fmt.Errorf("failed to query opsgenie teams: %w", err)
+		return "", err
 	}
+	return team.ID, nil
+}
+
+// InvalidateTeamCache drops the cached team directory, the next team lookup
+// fetches all teams again.
+func (c *JSMClient) InvalidateTeamCache() {
+	c.Teams.Invalidate()
+}
+
+// teamPageSize is the number of teams requested per page when walking the
+// allOpsgenieTeams connection.
+const teamPageSize = 100
+
+// ListOpsgenieTeams returns every Opsgenie team of the cloud site, following
+// the allOpsgenieTeams connection cursor across all pages.
+func (c *JSMClient) ListOpsgenieTeams(ctx context.Context) ([]Team, error) {
+	var teams []Team
+	var after *graphql.String
+	for {
+		var query struct {
+			Opsgenie struct {
+				AllOpsgenieTeams struct {
+					Edges []struct {
+						Node struct {
+							ID   string `json:"id"`
+							Name string `json:"name"`
+						} `json:"node"`
+					} `json:"edges"`
+					PageInfo struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+				} `graphql:"allOpsgenieTeams(cloudId: $cloudId, first: $first, after: $after)"`
+			} `graphql:"opsgenie"`
+		}
+
+		variables := map[string]any{
+			"cloudId": graphql.ID(c.CloudID),
+			"first":   graphql.Int(teamPageSize),
+			"after":   after,
+		}
+
+		if err := c.query(ctx, "ListOpsgenieTeams", &query, variables); err != nil {
+			return nil, err
+		}
+
+		for _, edge := range query.Opsgenie.AllOpsgenieTeams.Edges {
+			teams = append(teams, Team{ID: edge.Node.ID, Name: edge.Node.Name})
+		}
 
-	for _, edge := range query.Opsgenie.AllOpsgenieTeams.Edges {
-		if edge.Node.Name == name {
-			return edge.Node.ID, nil
+		pageInfo := query.Opsgenie.AllOpsgenieTeams.PageInfo
+		if !pageInfo.HasNextPage || pageInfo.EndCursor == "" {
+			break
 		}
+		cursor := graphql.String(pageInfo.EndCursor)
+		after = &cursor
 	}
 
-	return "", fmt.Errorf("opsgenie team with name %q not found", name)
+	return teams, nil
 }
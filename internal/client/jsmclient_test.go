@@ -0,0 +1,127 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// servicePages serves the given service names over a devOpsServices
+// connection, one page per inner slice.
+func servicePages(pages [][]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Variables map[string]any `json:"variables"`
+		}
+		Expect(json.NewDecoder(r.Body).Decode(&payload)).To(Succeed())
+
+		page := 0
+		if after, ok := payload.Variables["after"].(string); ok {
+			_, err := fmt.Sscanf(after, "page-%d", &page)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		edges := []map[string]any{}
+		for _, name := range pages[page] {
+			edges = append(edges, map[string]any{"node": map[string]any{
+				"id": "ari:service/" + name, "name": name, "revision": "1",
+			}})
+		}
+		hasNext := page+1 < len(pages)
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{
+			"devOpsServices": map[string]any{
+				"edges": edges,
+				"pageInfo": map[string]any{
+					"hasNextPage": hasNext,
+					"endCursor":   fmt.Sprintf("page-%d", page+1),
+				},
+			},
+		}})
+	}
+}
+
+func newTestClient(url string) *JSMClient {
+	c, err := NewJSMClient(JSMConfig{
+		GraphQLURL: url,
+		RestURL:    url,
+		Token:      "token",
+		Username:   "user",
+		CloudID:    "cloud",
+	})
+	Expect(err).NotTo(HaveOccurred())
+	return c
+}
+
+var _ = Describe("GetServiceByName", func() {
+	var server *httptest.Server
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("ignores services whose name only contains the requested one", func() {
+		server = httptest.NewServer(servicePages([][]string{{"payments-api", "api-gateway"}, {"api"}}))
+		c := newTestClient(server.URL)
+
+		svc, err := c.GetServiceByName(context.Background(), "api")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(svc).NotTo(BeNil())
+		Expect(svc.ID).To(Equal("ari:service/api"))
+	})
+
+	It("returns nil when nothing matches exactly", func() {
+		server = httptest.NewServer(servicePages([][]string{{"payments-api"}}))
+		c := newTestClient(server.URL)
+
+		svc, err := c.GetServiceByName(context.Background(), "api")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(svc).To(BeNil())
+	})
+
+	It("matches case-insensitively when configured", func() {
+		server = httptest.NewServer(servicePages([][]string{{"API"}}))
+		c := newTestClient(server.URL)
+
+		svc, err := c.GetServiceByName(context.Background(), "api")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(svc).To(BeNil())
+
+		c.CaseInsensitiveNames = true
+		svc, err = c.GetServiceByName(context.Background(), "api")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(svc).NotTo(BeNil())
+	})
+
+	It("reports an ambiguous name across pages", func() {
+		server = httptest.NewServer(servicePages([][]string{{"Api"}, {"api"}}))
+		c := newTestClient(server.URL)
+		c.CaseInsensitiveNames = true
+
+		_, err := c.GetServiceByName(context.Background(), "api")
+		var ambiguous *AmbiguousNameError
+		Expect(errors.As(err, &ambiguous)).To(BeTrue())
+		Expect(ambiguous.IDs).To(ConsistOf("ari:service/Api", "ari:service/api"))
+	})
+})
@@ -0,0 +1,152 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Outcomes of a JSM API request, used as the outcome label.
+const (
+	outcomeSuccess     = "success"
+	outcomeConflict    = "conflict"
+	outcomeRateLimited = "rate_limited"
+	outcomeAuthError   = "auth_error"
+	outcomeServerError = "server_error"
+	outcomeNotFound    = "not_found"
+	outcomeInvalid     = "invalid"
+	outcomeError       = "error"
+)
+
+var (
+	apiRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "jsm_api_requests_total",
+		Help: "Number of JSM API requests by operation and outcome.",
+	}, []string{"operation", "outcome"})
+
+	apiRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "jsm_api_request_duration_seconds",
+		Help: "Latency of JSM API requests by operation, including retries and rate limit waits.",
+		// Atlassian usually answers within a second, the long tail comes from
+		// rate limit waits and retries
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"operation"})
+
+	apiRequestsInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "jsm_api_requests_in_flight",
+		Help: "Number of JSM API requests currently in flight by operation.",
+	}, []string{"operation"})
+
+	apiRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "jsm_api_retries_total",
+		Help: "Number of retried JSM API request attempts by operation.",
+	}, []string{"operation"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(apiRequests, apiRequestDuration, apiRequestsInFlight, apiRetries)
+}
+
+type operationKey struct{}
+
+// instrument starts measuring the API operation op. It returns the context its
+// requests must be sent with and a function that records the outcome.
+func instrument(ctx context.Context, op string) (context.Context, func(err error)) {
+	inFlight := apiRequestsInFlight.WithLabelValues(op)
+	inFlight.Inc()
+	start := time.Now()
+
+	return context.WithValue(ctx, operationKey{}, op), func(err error) {
+		inFlight.Dec()
+		apiRequestDuration.WithLabelValues(op).Observe(time.Since(start).Seconds())
+		apiRequests.WithLabelValues(op, outcome(err)).Inc()
+	}
+}
+
+// outcome maps an error returned by the client to the outcome label.
+func outcome(err error) string {
+	var (
+		conflict      *RevisionConflictError
+		alreadyExists *AlreadyExistsError
+		rateLimited   *RateLimitedError
+		authErr       *AuthError
+		serverErr     *ServerError
+		notFound      *NotFoundError
+		validation    *ValidationError
+	)
+	switch {
+	case err == nil:
+		return outcomeSuccess
+	case errors.As(err, &conflict), errors.As(err, &alreadyExists):
+		return outcomeConflict
+	case errors.As(err, &rateLimited):
+		return outcomeRateLimited
+	case errors.As(err, &authErr):
+		return outcomeAuthError
+	case errors.As(err, &serverErr):
+		return outcomeServerError
+	case errors.As(err, &notFound):
+		return outcomeNotFound
+	case errors.As(err, &validation):
+		return outcomeInvalid
+	}
+	return outcomeError
+}
+
+// operationName returns the operation label of a request: the name given to
+// instrument, the GraphQL operation name or the method and path of a REST
+// call.
+func operationName(req *http.Request) string {
+	if op, ok := req.Context().Value(operationKey{}).(string); ok {
+		return op
+	}
+	if op, ok := graphQLOperation(req); ok && op.name != "" {
+		return op.name
+	}
+	return req.Method + " " + restPath(req.URL.Path)
+}
+
+// restPath replaces the identifiers in a REST path with a placeholder, so the
+// operation label doesn't grow with every object. API versions like "v1" are
+// kept.
+func restPath(p string) string {
+	segments := strings.Split(path.Clean("/"+p), "/")
+	for i, s := range segments {
+		if apiVersion.MatchString(s) {
+			continue
+		}
+		if strings.ContainsAny(s, "0123456789:") {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+var apiVersion = regexp.MustCompile(`^v[0-9]+$`)
+
+// metricsTransport records the requests that weren't sent through
+// JSMClient.query or JSMClient.mutate, i.e. the calls of the REST client.
+type metricsTransport struct {
+	base http.RoundTripper
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if _, ok := req.Context().Value(operationKey{}).(string); ok {
+		return t.base.RoundTrip(req)
+	}
+	_, done := instrument(req.Context(), operationName(req))
+	resp, err := t.base.RoundTrip(req)
+	if err == nil && resp.StatusCode >= http.StatusBadRequest {
+		done(statusError(resp.StatusCode, "", resp.Header))
+	} else {
+		done(err)
+	}
+	return resp, err
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("API metrics", func() {
+	var server *httptest.Server
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	requests := func(op, outcome string) float64 {
+		return testutil.ToFloat64(apiRequests.WithLabelValues(op, outcome))
+	}
+
+	It("counts requests by operation and outcome", func() {
+		server = httptest.NewServer(servicePages([][]string{{"api"}}))
+		c := newTestClient(server.URL)
+		successes := requests("GetServiceByName", outcomeSuccess)
+		observed := testutil.CollectAndCount(apiRequestDuration)
+
+		_, err := c.GetServiceByName(context.Background(), "api")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requests("GetServiceByName", outcomeSuccess)).To(Equal(successes + 1))
+		Expect(testutil.CollectAndCount(apiRequestDuration)).To(BeNumerically(">=", max(observed, 1)))
+		Expect(testutil.ToFloat64(apiRequestsInFlight.WithLabelValues("GetServiceByName"))).To(BeZero())
+	})
+
+	It("counts a conflict reported in a mutation payload", func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"data":{"updateDevOpsService":{"success":false,"errors":[` +
+				`{"message":"Specified revision was incorrect","extensions":{"statusCode":409}}]}}}`))
+		}))
+		c := newTestClient(server.URL)
+		conflicts := requests("UpdateDevOpsService", outcomeConflict)
+
+		_, err := c.UpdateService(context.Background(), &UpdateServiceRequest{ID: "ari:service/api", Revision: "1"})
+		Expect(err).To(HaveOccurred())
+		Expect(requests("UpdateDevOpsService", outcomeConflict)).To(Equal(conflicts + 1))
+	})
+
+	It("counts retries and the final outcome once", func() {
+		var calls atomic.Int32
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			servicePages([][]string{{}})(w, r)
+		}))
+		c, err := NewJSMClient(JSMConfig{
+			GraphQLURL: server.URL,
+			RestURL:    server.URL,
+			Token:      "token",
+			Username:   "user",
+			CloudID:    "cloud",
+			RateLimit:  RateLimitConfig{MaxRetries: 1},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		retries := testutil.ToFloat64(apiRetries.WithLabelValues("GetServiceByName"))
+		successes := requests("GetServiceByName", outcomeSuccess)
+		serverErrors := requests("GetServiceByName", outcomeServerError)
+
+		_, err = c.GetServiceByName(context.Background(), "api")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(testutil.ToFloat64(apiRetries.WithLabelValues("GetServiceByName"))).To(Equal(retries + 1))
+		Expect(requests("GetServiceByName", outcomeSuccess)).To(Equal(successes + 1))
+		Expect(requests("GetServiceByName", outcomeServerError)).To(Equal(serverErrors))
+	})
+
+	It("labels REST calls by method and path", func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "no such team", http.StatusNotFound)
+		}))
+		c := newTestClient(server.URL)
+		op := "GET /v1/teams/{id}"
+		notFound := requests(op, outcomeNotFound)
+
+		req, err := c.JiraClient.NewRequest(http.MethodGet, "teams/8f3c1e2a", nil)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = c.JiraClient.Do(req, nil)
+		Expect(err).To(HaveOccurred())
+		Expect(requests(op, outcomeNotFound)).To(Equal(notFound + 1))
+	})
+})
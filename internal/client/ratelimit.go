@@ -0,0 +1,305 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Defaults for RateLimitConfig.
+const (
+	DefaultRateLimitQPS   = 10
+	DefaultRateLimitBurst = 20
+	DefaultMaxRetries     = 3
+	DefaultMaxRetryWait   = time.Minute
+
+	// retryBaseDelay is the first backoff step when the server gave no hint.
+	retryBaseDelay = 500 * time.Millisecond
+)
+
+// RateLimitConfig configures the request budget shared by every request the
+// operator sends to Atlassian and how throttled requests are retried.
+type RateLimitConfig struct {
+	// QPS is the sustained number of requests per second, zero or less
+	// disables the budget.
+	QPS float64
+	// Burst is the number of requests that may be sent at once.
+	Burst int
+	// MaxRetries is how many times an idempotent request is retried after a
+	// 429, a 502-504 or a network error. Mutations are never retried.
+	MaxRetries int
+	// MaxRetryWait is the longest delay the transport waits for before a
+	// retry. If the server asks for more the error is returned instead, so
+	// the reconciler can requeue rather than block a worker.
+	MaxRetryWait time.Duration
+}
+
+// rateLimitTransport applies the operator-wide token bucket, honours the
+// Retry-After and X-RateLimit-* headers of Atlassian and retries idempotent
+// requests with jittered exponential backoff.
+type rateLimitTransport struct {
+	base         http.RoundTripper
+	limiter      *rate.Limiter
+	maxRetries   int
+	maxRetryWait time.Duration
+
+	// sleep and now are replaced in tests
+	sleep func(ctx context.Context, d time.Duration) error
+	now   func() time.Time
+
+	mu sync.Mutex
+	// pausedUntil is set when Atlassian told us the budget is exhausted, every
+	// request waits until then, not only the one that was throttled
+	pausedUntil time.Time
+}
+
+func newRateLimitTransport(base http.RoundTripper, cfg RateLimitConfig) *rateLimitTransport {
+	limit := rate.Inf
+	if cfg.QPS > 0 {
+		limit = rate.Limit(cfg.QPS)
+	}
+	burst := cfg.Burst
+	if burst <= 0 {
+		burst = 1
+	}
+	maxRetryWait := cfg.MaxRetryWait
+	if maxRetryWait <= 0 {
+		maxRetryWait = DefaultMaxRetryWait
+	}
+	return &rateLimitTransport{
+		base:         base,
+		limiter:      rate.NewLimiter(limit, burst),
+		maxRetries:   max(cfg.MaxRetries, 0),
+		maxRetryWait: maxRetryWait,
+		sleep:        sleepContext,
+		now:          time.Now,
+	}
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	retryable := isIdempotent(req)
+
+	for attempt := 0; ; attempt++ {
+		if err := t.wait(ctx); err != nil {
+			return nil, err
+		}
+
+		attemptReq := req
+		if attempt > 0 {
+			var err error
+			if attemptReq, err = rewind(req); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+		if resp != nil {
+			t.observe(resp)
+		}
+
+		if !retryable || attempt >= t.maxRetries {
+			return resp, err
+		}
+		delay, retry := t.retryDelay(ctx, resp, err, attempt)
+		if !retry {
+			return resp, err
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			_ = resp.Body.Close()
+		}
+		if err := t.sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+		apiRetries.WithLabelValues(operationName(req)).Inc()
+	}
+}
+
+// wait blocks until a global pause is over and a token is available.
+func (t *rateLimitTransport) wait(ctx context.Context) error {
+	t.mu.Lock()
+	pause := t.pausedUntil.Sub(t.now())
+	t.mu.Unlock()
+
+	if pause > 0 {
+		if err := t.sleep(ctx, pause); err != nil {
+			return err
+		}
+	}
+	return t.limiter.Wait(ctx)
+}
+
+// observe pauses all requests when Atlassian reports that the budget is used
+// up, either with a 429 or with X-RateLimit-Remaining: 0.
+func (t *rateLimitTransport) observe(resp *http.Response) {
+	now := t.now()
+	var until time.Time
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"), now); retryAfter > 0 {
+			until = now.Add(retryAfter)
+		}
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset := parseRateLimitReset(resp.Header.Get("X-RateLimit-Reset"), now); reset.After(until) {
+			until = reset
+		}
+	}
+
+	if until.IsZero() {
+		return
+	}
+	// never block for longer than a retry would wait for
+	if limit := now.Add(t.maxRetryWait); until.After(limit) {
+		until = limit
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if until.After(t.pausedUntil) {
+		t.pausedUntil = until
+	}
+}
+
+// retryDelay decides whether a failed idempotent attempt is retried and how
+// long to wait before doing so.
+func (t *rateLimitTransport) retryDelay(ctx context.Context, resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, false
+		}
+		return jitter(backoff(attempt)), true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+	default:
+		return 0, false
+	}
+
+	delay := jitter(backoff(attempt))
+	if retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"), t.now()); retryAfter > 0 {
+		// spread the retries of all the workers that were throttled together
+		delay = retryAfter + jitter(retryAfter/10)
+	}
+	if delay > t.maxRetryWait {
+		return 0, false
+	}
+	return delay, true
+}
+
+// backoff returns the exponential delay for the given attempt.
+func backoff(attempt int) time.Duration {
+	return retryBaseDelay << min(attempt, 10)
+}
+
+// jitter returns a random delay between half and the full given delay.
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + rand.N(d-half+1)
+}
+
+// parseRateLimitReset understands the forms of X-RateLimit-Reset used by
+// Atlassian APIs: an ISO 8601 timestamp, a unix timestamp or a number of
+// seconds from now.
+func parseRateLimitReset(value string, now time.Time) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	if at, err := time.Parse(time.RFC3339, value); err == nil {
+		return at
+	}
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil && n > 0 {
+		// anything that looks like a unix timestamp is treated as one
+		if n > 1_000_000_000 {
+			return time.Unix(n, 0)
+		}
+		return now.Add(time.Duration(n) * time.Second)
+	}
+	return time.Time{}
+}
+
+// isIdempotent reports whether a request can safely be sent twice: safe HTTP
+// methods and GraphQL queries, but never GraphQL mutations.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	case http.MethodPost:
+		op, ok := graphQLOperation(req)
+		return ok && !op.mutation
+	}
+	return false
+}
+
+// graphQLOp describes the GraphQL operation carried by a request.
+type graphQLOp struct {
+	name     string
+	mutation bool
+}
+
+// graphQLOperation peeks at the body of a GraphQL request without consuming it.
+func graphQLOperation(req *http.Request) (graphQLOp, bool) {
+	if req.GetBody == nil {
+		return graphQLOp{}, false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return graphQLOp{}, false
+	}
+	defer body.Close()
+
+	var payload struct {
+		Query         string `json:"query"`
+		OperationName string `json:"operationName"`
+	}
+	if err := json.NewDecoder(body).Decode(&payload); err != nil || payload.Query == "" {
+		return graphQLOp{}, false
+	}
+	return graphQLOp{
+		name:     payload.OperationName,
+		mutation: strings.HasPrefix(strings.TrimSpace(payload.Query), "mutation"),
+	}, true
+}
+
+// rewind returns a copy of req with a fresh body, so it can be sent again.
+func rewind(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return clone, nil
+	}
+	if req.GetBody == nil {
+		return nil, errors.New("request body can't be replayed")
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	clone.Body = body
+	return clone, nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("rateLimitTransport", func() {
+	var (
+		server    *httptest.Server
+		responses []func(http.ResponseWriter)
+		bodies    []string
+		transport *rateLimitTransport
+		slept     []time.Duration
+		now       time.Time
+	)
+
+	BeforeEach(func() {
+		responses = nil
+		bodies = nil
+		slept = nil
+		now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+			next := responses[0]
+			responses = responses[1:]
+			next(w)
+		}))
+		transport = newRateLimitTransport(http.DefaultTransport, RateLimitConfig{MaxRetries: 2})
+		transport.now = func() time.Time { return now }
+		transport.sleep = func(_ context.Context, d time.Duration) error {
+			slept = append(slept, d)
+			now = now.Add(d)
+			return nil
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	throttled := func(retryAfter string) func(http.ResponseWriter) {
+		return func(w http.ResponseWriter) {
+			w.Header().Set("Retry-After", retryAfter)
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}
+	ok := func(w http.ResponseWriter) { w.WriteHeader(http.StatusOK) }
+
+	post := func(query string) (*http.Response, error) {
+		req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"query":"`+query+`"}`))
+		Expect(err).NotTo(HaveOccurred())
+		return transport.RoundTrip(req)
+	}
+
+	It("retries queries after the Retry-After delay with the same body", func() {
+		responses = append(responses, throttled("2"), ok)
+
+		resp, err := post("query Q{a}")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(bodies).To(HaveLen(2))
+		Expect(bodies[1]).To(Equal(bodies[0]))
+		// the retry waits at least as long as asked, plus some jitter
+		Expect(slept).To(HaveLen(1))
+		Expect(slept[0]).To(BeNumerically(">=", 2*time.Second))
+		Expect(slept[0]).To(BeNumerically("<=", 2200*time.Millisecond))
+	})
+
+	It("never retries mutations", func() {
+		responses = append(responses, throttled("2"))
+
+		resp, err := post("mutation M{a}")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusTooManyRequests))
+		Expect(bodies).To(HaveLen(1))
+	})
+
+	It("gives up when the server asks to wait longer than allowed", func() {
+		responses = append(responses, throttled("3600"))
+
+		resp, err := post("query Q{a}")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusTooManyRequests))
+		Expect(bodies).To(HaveLen(1))
+	})
+
+	It("pauses every request once the budget is exhausted", func() {
+		responses = append(responses, func(w http.ResponseWriter) {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", now.Add(10*time.Second).Format(time.RFC3339))
+			w.WriteHeader(http.StatusOK)
+		}, ok)
+
+		_, err := post("mutation M{a}")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(slept).To(BeEmpty())
+
+		_, err = post("mutation M{a}")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(slept).To(ConsistOf(10 * time.Second))
+	})
+
+	It("tells GraphQL queries and mutations apart", func() {
+		query, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"query":"query Q{a}","operationName":"Q"}`))
+		mutation, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"query":"mutation M{a}"}`))
+		get, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		del, _ := http.NewRequest(http.MethodDelete, server.URL, nil)
+
+		Expect(isIdempotent(query)).To(BeTrue())
+		Expect(isIdempotent(mutation)).To(BeFalse())
+		Expect(isIdempotent(get)).To(BeTrue())
+		Expect(isIdempotent(del)).To(BeFalse())
+	})
+})
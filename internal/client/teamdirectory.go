@@ -0,0 +1,155 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultTeamCacheTTL is used when no team cache TTL is configured.
+const DefaultTeamCacheTTL = 5 * time.Minute
+
+// sharedLoadTimeout bounds a load shared by concurrent lookups. Such a load
+// doesn't end with the context of the lookup that started it.
+const sharedLoadTimeout = 2 * time.Minute
+
+// Team is an Opsgenie team known to the team directory.
+type Team struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// TeamDirectory keeps an in-memory name -> ARI index of all Opsgenie teams.
+// The index is loaded lazily, reloaded once it is older than the TTL and can
+// be dropped explicitly with Invalidate, e.g. after a team was not found.
+// Concurrent lookups share a single load, which runs without holding the lock
+// and isn't cancelled with the lookup that started it.
+type TeamDirectory struct {
+	client *JSMClient
+	ttl    time.Duration
+	now    func() time.Time
+	loads  singleflight.Group
+
+	mu       sync.Mutex
+	byName   map[string][]Team
+	loadedAt time.Time
+	// generation is bumped by Invalidate, so a load that was already running
+	// doesn't cache the teams it fetched before.
+	generation int
+}
+
+// NewTeamDirectory creates a team directory backed by the given client.
+// A zero ttl means DefaultTeamCacheTTL.
+func NewTeamDirectory(c *JSMClient, ttl time.Duration) *TeamDirectory {
+	if ttl <= 0 {
+		ttl = DefaultTeamCacheTTL
+	}
+	return &TeamDirectory{
+		client: c,
+		ttl:    ttl,
+		now:    time.Now,
+	}
+}
+
+// Lookup returns the team with the given name. Names are compared the same way
+// as everywhere else in the client (see JSMClient.CaseInsensitiveNames). It
+// returns an *AmbiguousNameError if several teams share the name.
+func (d *TeamDirectory) Lookup(ctx context.Context, name string) (*Team, error) {
+	byName, err := d.index(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []Team
+	if d.client.CaseInsensitiveNames {
+		matches = byName[strings.ToLower(name)]
+	} else {
+		// the index is keyed case-insensitively, so narrow it down to exact names
+		for _, team := range byName[strings.ToLower(name)] {
+			if team.Name == name {
+				matches = append(matches, team)
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, &NotFoundError{Message: fmt.Sprintf("opsgenie team with name %q not found", name)}
+	case 1:
+		return &matches[0], nil
+	default:
+		ids := make([]string, 0, len(matches))
+		for _, team := range matches {
+			ids = append(ids, team.ID)
+		}
+		return nil, &AmbiguousNameError{Kind: "opsgenie team", Name: name, IDs: ids}
+	}
+}
+
+// Invalidate drops the cached index, the next Lookup reloads it.
+func (d *TeamDirectory) Invalidate() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.byName = nil
+	d.generation++
+}
+
+// index returns the name index, loading it if it is missing or older than the
+// TTL. The index is never modified once built, so it is read without the lock.
+// A caller whose context ends stops waiting, the shared load carries on.
+func (d *TeamDirectory) index(ctx context.Context) (map[string][]Team, error) {
+	d.mu.Lock()
+	byName := d.byName
+	fresh := byName != nil && d.now().Sub(d.loadedAt) <= d.ttl
+	d.mu.Unlock()
+	if fresh {
+		return byName, nil
+	}
+
+	loads := d.loads.DoChan("teams", func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sharedLoadTimeout)
+		defer cancel()
+		return d.load(ctx)
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case loaded := <-loads:
+		if loaded.Err != nil {
+			return nil, loaded.Err
+		}
+		return loaded.Val.(map[string][]Team), nil
+	}
+}
+
+// load fetches every team and builds a new index, which replaces the cached
+// one unless the directory was invalidated in the meantime.
+func (d *TeamDirectory) load(ctx context.Context) (map[string][]Team, error) {
+	d.mu.Lock()
+	generation := d.generation
+	d.mu.Unlock()
+
+	teams, err := d.client.ListOpsgenieTeams(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string][]Team, len(teams))
+	for _, team := range teams {
+		key := strings.ToLower(team.Name)
+		byName[key] = append(byName[key], team)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.generation == generation {
+		d.byName = byName
+		d.loadedAt = d.now()
+	}
+	return byName, nil
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// teamPages serves the given team names over the allOpsgenieTeams connection,
+// one page per inner slice, and counts the requests it receives.
+func teamPages(pages [][]string, requests *atomic.Int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		var payload struct {
+			Variables map[string]any `json:"variables"`
+		}
+		Expect(json.NewDecoder(r.Body).Decode(&payload)).To(Succeed())
+
+		page := 0
+		if after, ok := payload.Variables["after"].(string); ok {
+			_, err := fmt.Sscanf(after, "page-%d", &page)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		edges := []map[string]any{}
+		for _, name := range pages[page] {
+			edges = append(edges, map[string]any{"node": map[string]any{
+				"id": "ari:team/" + name, "name": name,
+			}})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{
+			"opsgenie": map[string]any{
+				"allOpsgenieTeams": map[string]any{
+					"edges": edges,
+					"pageInfo": map[string]any{
+						"hasNextPage": page+1 < len(pages),
+						"endCursor":   fmt.Sprintf("page-%d", page+1),
+					},
+				},
+			},
+		}})
+	}
+}
+
+var _ = Describe("TeamDirectory", func() {
+	var (
+		server   *httptest.Server
+		requests atomic.Int32
+		c        *JSMClient
+	)
+
+	BeforeEach(func() {
+		requests.Store(0)
+		server = httptest.NewServer(teamPages([][]string{{"SRE", "Core"}, {"Payments", "Dup"}, {"dup"}}, &requests))
+		c = newTestClient(server.URL)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("resolves teams beyond the first page and serves repeated lookups from cache", func() {
+		id, err := c.GetOpsgenieTeamIDByName(context.Background(), "Payments")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(Equal("ari:team/Payments"))
+		Expect(requests.Load()).To(BeEquivalentTo(3))
+
+		id, err = c.GetOpsgenieTeamIDByName(context.Background(), "SRE")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(Equal("ari:team/SRE"))
+		Expect(requests.Load()).To(BeEquivalentTo(3))
+	})
+
+	It("reloads the index after the TTL expires or on invalidation", func() {
+		now := time.Now()
+		c.Teams.now = func() time.Time { return now }
+
+		_, err := c.Teams.Lookup(context.Background(), "SRE")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requests.Load()).To(BeEquivalentTo(3))
+
+		now = now.Add(DefaultTeamCacheTTL + time.Second)
+		_, err = c.Teams.Lookup(context.Background(), "SRE")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requests.Load()).To(BeEquivalentTo(6))
+
+		c.Teams.Invalidate()
+		_, err = c.Teams.Lookup(context.Background(), "SRE")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requests.Load()).To(BeEquivalentTo(9))
+	})
+
+	It("shares a load between lookups without blocking invalidation", func() {
+		release := make(chan struct{})
+		pages := teamPages([][]string{{"SRE"}}, &requests)
+		server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+			pages(w, r)
+		})
+
+		var wg sync.WaitGroup
+		for range 2 {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				team, err := c.Teams.Lookup(context.Background(), "SRE")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(team.ID).To(Equal("ari:team/SRE"))
+			}()
+		}
+		time.Sleep(50 * time.Millisecond)
+
+		invalidated := make(chan struct{})
+		go func() {
+			c.Teams.Invalidate()
+			close(invalidated)
+		}()
+		Eventually(invalidated).Should(BeClosed())
+
+		close(release)
+		wg.Wait()
+		Expect(requests.Load()).To(BeEquivalentTo(1))
+	})
+
+	It("keeps a shared load going when the lookup that started it is cancelled", func() {
+		release := make(chan struct{})
+		pages := teamPages([][]string{{"SRE"}}, &requests)
+		server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+			pages(w, r)
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancelled := make(chan error, 1)
+		go func() {
+			_, err := c.Teams.Lookup(ctx, "SRE")
+			cancelled <- err
+		}()
+		time.Sleep(50 * time.Millisecond)
+
+		waiter := make(chan error, 1)
+		go func() {
+			_, err := c.Teams.Lookup(context.Background(), "SRE")
+			waiter <- err
+		}()
+		time.Sleep(50 * time.Millisecond)
+
+		cancel()
+		Eventually(cancelled).Should(Receive(MatchError(context.Canceled)))
+		Consistently(waiter, 100*time.Millisecond).ShouldNot(Receive())
+
+		close(release)
+		Eventually(waiter).Should(Receive(BeNil()))
+		Expect(requests.Load()).To(BeEquivalentTo(1))
+	})
+
+	It("matches exactly unless case-insensitive names are enabled", func() {
+		_, err := c.Teams.Lookup(context.Background(), "sre")
+		Expect(err).To(HaveOccurred())
+
+		team, err := c.Teams.Lookup(context.Background(), "Dup")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(team.ID).To(Equal("ari:team/Dup"))
+
+		c.CaseInsensitiveNames = true
+		team, err = c.Teams.Lookup(context.Background(), "sre")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(team.ID).To(Equal("ari:team/SRE"))
+	})
+
+	It("reports duplicate names", func() {
+		c.CaseInsensitiveNames = true
+
+		_, err := c.Teams.Lookup(context.Background(), "dup")
+		var ambiguous *AmbiguousNameError
+		Expect(errors.As(err, &ambiguous)).To(BeTrue())
+		Expect(ambiguous.IDs).To(ConsistOf("ari:team/Dup", "ari:team/dup"))
+	})
+})
@@ -0,0 +1,177 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// TeamMemberRole is the role of a user in an Opsgenie team.
+type TeamMemberRole string
+
+const (
+	// TeamMemberRoleAdmin members can manage the team.
+	TeamMemberRoleAdmin TeamMemberRole = "admin"
+	// TeamMemberRoleUser is the role of regular members.
+	TeamMemberRoleUser TeamMemberRole = "user"
+)
+
+// TeamMember is a user in an Opsgenie team, identified by the username, i.e.
+// the email address, of their account.
+type TeamMember struct {
+	Username string         `json:"username"`
+	Role     TeamMemberRole `json:"role"`
+}
+
+// OpsgenieTeam is the full state of an Opsgenie team as managed through the
+// REST teams API.
+type OpsgenieTeam struct {
+	// ID is the ARI of the team, the same ID the GraphQL API uses.
+	ID          string       `json:"id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Members     []TeamMember `json:"members,omitempty"`
+}
+
+// OpsgenieTeamRequest is the desired state of an Opsgenie team. The members
+// replace the current ones, so an empty list removes them all, while nil
+// members leave them unchanged on update.
+type OpsgenieTeamRequest struct {
+	Name        string
+	Description string
+	Members     []TeamMember
+}
+
+// restTeam is an Opsgenie team as sent to and returned by the REST API, which
+// identifies teams by the last segment of their ARI. Members are a pointer, so
+// a request can leave them out as well as send an empty list.
+type restTeam struct {
+	ID          string            `json:"id,omitempty"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Members     *[]restTeamMember `json:"members,omitempty"`
+}
+
+type restTeamMember struct {
+	User struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	Role string `json:"role,omitempty"`
+}
+
+// restTeamResponse wraps every team returned by the REST API.
+type restTeamResponse struct {
+	Data restTeam `json:"data"`
+}
+
+// GetOpsgenieTeam returns the Opsgenie team with the given ARI including its
+// members. It returns a *NotFoundError if the team does not exist.
+func (c *JSMClient) GetOpsgenieTeam(ctx context.Context, id string) (*OpsgenieTeam, error) {
+	var resp restTeamResponse
+	if err := c.rest(ctx, "GetOpsgenieTeam", http.MethodGet, teamPath(id), nil, &resp); err != nil {
+		return nil, err
+	}
+
+	team := &OpsgenieTeam{
+		ID:          c.teamARI(resp.Data.ID),
+		Name:        resp.Data.Name,
+		Description: resp.Data.Description,
+	}
+	if resp.Data.Members != nil {
+		for _, m := range *resp.Data.Members {
+			team.Members = append(team.Members, TeamMember{Username: m.User.Username, Role: TeamMemberRole(m.Role)})
+		}
+	}
+	return team, nil
+}
+
+// CreateOpsgenieTeam creates an Opsgenie team with the given members and
+// returns it. The team directory is dropped, so lookups by name see it.
+func (c *JSMClient) CreateOpsgenieTeam(ctx context.Context, req *OpsgenieTeamRequest) (*OpsgenieTeam, error) {
+	var resp restTeamResponse
+	if err := c.rest(ctx, "CreateOpsgenieTeam", http.MethodPost, "teams", newRestTeam(req), &resp); err != nil {
+		return nil, err
+	}
+	c.InvalidateTeamCache()
+
+	return &OpsgenieTeam{
+		ID:          c.teamARI(resp.Data.ID),
+		Name:        req.Name,
+		Description: req.Description,
+		Members:     req.Members,
+	}, nil
+}
+
+// UpdateOpsgenieTeam replaces the name, description and members of the
+// Opsgenie team with the given ARI.
+func (c *JSMClient) UpdateOpsgenieTeam(ctx context.Context, id string, req *OpsgenieTeamRequest) error {
+	if err := c.rest(ctx, "UpdateOpsgenieTeam", http.MethodPatch, teamPath(id), newRestTeam(req), nil); err != nil {
+		return err
+	}
+	// the team may have been renamed
+	c.InvalidateTeamCache()
+	return nil
+}
+
+// DeleteOpsgenieTeam deletes the Opsgenie team with the given ARI.
+func (c *JSMClient) DeleteOpsgenieTeam(ctx context.Context, id string) error {
+	if err := c.rest(ctx, "DeleteOpsgenieTeam", http.MethodDelete, teamPath(id), nil, nil); err != nil {
+		return err
+	}
+	c.InvalidateTeamCache()
+	return nil
+}
+
+// rest sends a request named op to the REST API and records it in the API
+// metrics. The path is relative to the REST base URL, body and v are encoded
+// and decoded as JSON.
+func (c *JSMClient) rest(ctx context.Context, op, method, path string, body, v any) (err error) {
+	ctx, done := instrument(ctx, op)
+	defer func() { done(err) }()
+
+	req, err := c.JiraClient.NewRequestWithContext(ctx, method, path, body)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	resp, err := c.JiraClient.Do(req, v)
+	if resp != nil && (err != nil || v == nil) {
+		if err != nil && resp.StatusCode >= http.StatusBadRequest {
+			err = errorFromResponse(resp.Response)
+		}
+		// Do only closes the body when it decodes it
+		_ = resp.Body.Close()
+	}
+	return classifyError(op, err)
+}
+
+func newRestTeam(req *OpsgenieTeamRequest) restTeam {
+	team := restTeam{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	if req.Members == nil {
+		return team
+	}
+	members := make([]restTeamMember, 0, len(req.Members))
+	for _, m := range req.Members {
+		var member restTeamMember
+		member.User.Username = m.Username
+		member.Role = string(m.Role)
+		members = append(members, member)
+	}
+	team.Members = &members
+	return team
+}
+
+// teamARI turns the ID the REST API uses for a team into its ARI.
+func (c *JSMClient) teamARI(teamID string) string {
+	return fmt.Sprintf("ari:cloud:opsgenie::team/%s/%s", c.CloudID, teamID)
+}
+
+// teamPath returns the REST path of the team with the given ARI. The REST API
+// knows the team by the last segment of it.
+func teamPath(id string) string {
+	return "teams/" + url.PathEscape(id[strings.LastIndex(id, "/")+1:])
+}
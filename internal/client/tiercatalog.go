@@ -0,0 +1,193 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultTierCacheTTL is used when no tier cache TTL is configured. Tiers are
+// site-wide settings that rarely change, so they are kept longer than teams.
+const DefaultTierCacheTTL = 30 * time.Minute
+
+// tierMissReloadInterval is how long a lookup that misses trusts the cached
+// tiers before reloading them. It keeps a spec with an unknown tier from
+// fetching every tier on each reconcile.
+const tierMissReloadInterval = 30 * time.Second
+
+// Tier is a JSM service tier known to the tier catalog.
+type Tier struct {
+	ID          string `json:"id"`
+	Level       int    `json:"level"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// TierCatalog keeps an in-memory copy of the service tiers of the cloud site.
+// The catalog is loaded lazily and reloaded once it is older than the TTL. A
+// lookup that misses reloads it too, unless it was loaded within the last
+// tierMissReloadInterval, so tiers added in the meantime are found without
+// waiting for the TTL. Concurrent lookups share a single load, which runs
+// without holding the lock.
+type TierCatalog struct {
+	client *JSMClient
+	ttl    time.Duration
+	now    func() time.Time
+	loads  singleflight.Group
+
+	mu       sync.Mutex
+	tiers    []Tier
+	loadedAt time.Time
+	// generation is bumped by Invalidate, so a load that was already running
+	// doesn't cache the tiers it fetched before.
+	generation int
+}
+
+// NewTierCatalog creates a tier catalog backed by the given client.
+// A zero ttl means DefaultTierCacheTTL.
+func NewTierCatalog(c *JSMClient, ttl time.Duration) *TierCatalog {
+	if ttl <= 0 {
+		ttl = DefaultTierCacheTTL
+	}
+	return &TierCatalog{
+		client: c,
+		ttl:    ttl,
+		now:    time.Now,
+	}
+}
+
+// ByLevel returns the tier with the given level, or a *NotFoundError.
+func (t *TierCatalog) ByLevel(ctx context.Context, level int) (*Tier, error) {
+	tier, err := t.find(ctx, func(tier Tier) bool { return tier.Level == level })
+	if err != nil {
+		return nil, err
+	}
+	if tier == nil {
+		return nil, &NotFoundError{Message: fmt.Sprintf("no service tier found for level %d", level)}
+	}
+	return tier, nil
+}
+
+// ByName returns the tier with the given name, e.g. "Critical", or a
+// *NotFoundError. Tier names are compared ignoring letter case.
+func (t *TierCatalog) ByName(ctx context.Context, name string) (*Tier, error) {
+	tier, err := t.find(ctx, func(tier Tier) bool { return strings.EqualFold(tier.Name, name) })
+	if err != nil {
+		return nil, err
+	}
+	if tier == nil {
+		return nil, &NotFoundError{Message: fmt.Sprintf("no service tier found with name %q", name)}
+	}
+	return tier, nil
+}
+
+// List returns a copy of all known tiers.
+func (t *TierCatalog) List(ctx context.Context) ([]Tier, error) {
+	tiers, _, err := t.cached(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append([]Tier(nil), tiers...), nil
+}
+
+// Invalidate drops the cached tiers, the next lookup reloads them.
+func (t *TierCatalog) Invalidate() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.tiers = nil
+	t.generation++
+}
+
+// find returns the first tier matching the predicate, reloading the catalog
+// once if a cached copy older than tierMissReloadInterval has no match. It
+// returns nil if no tier matches.
+func (t *TierCatalog) find(ctx context.Context, match func(Tier) bool) (*Tier, error) {
+	tiers, loadedAt, err := t.cached(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if tier := matchTier(tiers, match); tier != nil || t.now().Sub(loadedAt) < tierMissReloadInterval {
+		return tier, nil
+	}
+
+	tiers, err = t.reload(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return matchTier(tiers, match), nil
+}
+
+func matchTier(tiers []Tier, match func(Tier) bool) *Tier {
+	for _, tier := range tiers {
+		if match(tier) {
+			return &tier
+		}
+	}
+	return nil
+}
+
+// cached returns the tiers and when they were loaded, loading them if they
+// are missing or older than the TTL. The tiers are never modified once
+// loaded, so they are read without the lock.
+func (t *TierCatalog) cached(ctx context.Context) ([]Tier, time.Time, error) {
+	t.mu.Lock()
+	tiers, loadedAt := t.tiers, t.loadedAt
+	t.mu.Unlock()
+	if tiers != nil && t.now().Sub(loadedAt) <= t.ttl {
+		return tiers, loadedAt, nil
+	}
+
+	tiers, err := t.reload(ctx)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return tiers, t.now(), nil
+}
+
+// reload loads the tiers, joining a load that is already running. A caller
+// whose context ends stops waiting, the shared load carries on.
+func (t *TierCatalog) reload(ctx context.Context) ([]Tier, error) {
+	loads := t.loads.DoChan("tiers", func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sharedLoadTimeout)
+		defer cancel()
+		return t.load(ctx)
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case loaded := <-loads:
+		if loaded.Err != nil {
+			return nil, loaded.Err
+		}
+		return loaded.Val.([]Tier), nil
+	}
+}
+
+// load fetches every tier, which replace the cached ones unless the catalog
+// was invalidated in the meantime.
+func (t *TierCatalog) load(ctx context.Context) ([]Tier, error) {
+	t.mu.Lock()
+	generation := t.generation
+	t.mu.Unlock()
+
+	tiers, err := t.client.ListServiceTiers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if tiers == nil {
+		tiers = []Tier{}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.generation == generation {
+		t.tiers = tiers
+		t.loadedAt = t.now()
+	}
+	return tiers, nil
+}
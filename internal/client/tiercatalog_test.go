@@ -0,0 +1,159 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// tierList serves the tiers returned by the given function over
+// devOpsServiceTiers and counts the requests it receives.
+func tierList(tiers func() []Tier, requests *atomic.Int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{
+			"devOpsServiceTiers": tiers(),
+		}})
+	}
+}
+
+var _ = Describe("TierCatalog", func() {
+	var (
+		server   *httptest.Server
+		requests atomic.Int32
+		mu       sync.Mutex
+		tiers    []Tier
+		c        *JSMClient
+	)
+
+	BeforeEach(func() {
+		requests.Store(0)
+		tiers = []Tier{
+			{ID: "ari:tier/1", Level: 1, Name: "Critical", Description: "Tier 1 service"},
+			{ID: "ari:tier/2", Level: 2, Name: "High", Description: "Tier 2 service"},
+		}
+		server = httptest.NewServer(tierList(func() []Tier {
+			mu.Lock()
+			defer mu.Unlock()
+			return append([]Tier(nil), tiers...)
+		}, &requests))
+		c = newTestClient(server.URL)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("resolves tiers by level and by name from one cached copy", func() {
+		tier, err := c.GetTierByLevel(context.Background(), 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tier.Name).To(Equal("High"))
+
+		tier, err = c.GetTierByName(context.Background(), "critical")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tier.ID).To(Equal("ari:tier/1"))
+		Expect(tier.Description).To(Equal("Tier 1 service"))
+
+		id, err := c.GetTierIDByLevel(context.Background(), 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(Equal("ari:tier/1"))
+		Expect(requests.Load()).To(BeEquivalentTo(1))
+	})
+
+	It("reloads after the TTL expires", func() {
+		now := time.Now()
+		c.Tiers.now = func() time.Time { return now }
+
+		_, err := c.Tiers.ByLevel(context.Background(), 1)
+		Expect(err).NotTo(HaveOccurred())
+
+		now = now.Add(DefaultTierCacheTTL + time.Second)
+		_, err = c.Tiers.ByLevel(context.Background(), 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requests.Load()).To(BeEquivalentTo(2))
+	})
+
+	It("reloads on a miss at most once per reload interval", func() {
+		now := time.Now()
+		c.Tiers.now = func() time.Time { return now }
+
+		_, err := c.Tiers.ByLevel(context.Background(), 1)
+		Expect(err).NotTo(HaveOccurred())
+
+		mu.Lock()
+		tiers = append(tiers, Tier{ID: "ari:tier/3", Level: 3, Name: "Medium"})
+		mu.Unlock()
+
+		_, err = c.Tiers.ByName(context.Background(), "Medium")
+		var notFound *NotFoundError
+		Expect(errors.As(err, &notFound)).To(BeTrue())
+		Expect(requests.Load()).To(BeEquivalentTo(1))
+
+		now = now.Add(tierMissReloadInterval)
+		tier, err := c.Tiers.ByName(context.Background(), "Medium")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tier.Level).To(Equal(3))
+		Expect(requests.Load()).To(BeEquivalentTo(2))
+
+		_, err = c.Tiers.ByName(context.Background(), "Sev0")
+		Expect(errors.As(err, &notFound)).To(BeTrue())
+		Expect(requests.Load()).To(BeEquivalentTo(2))
+	})
+
+	It("shares a load between lookups without blocking invalidation", func() {
+		release := make(chan struct{})
+		list := server.Config.Handler
+		server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+			list.ServeHTTP(w, r)
+		})
+
+		var wg sync.WaitGroup
+		for range 2 {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				tier, err := c.Tiers.ByLevel(context.Background(), 2)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(tier.Name).To(Equal("High"))
+			}()
+		}
+		time.Sleep(50 * time.Millisecond)
+
+		invalidated := make(chan struct{})
+		go func() {
+			c.Tiers.Invalidate()
+			close(invalidated)
+		}()
+		Eventually(invalidated).Should(BeClosed())
+
+		close(release)
+		wg.Wait()
+		Expect(requests.Load()).To(BeEquivalentTo(1))
+	})
+})
@@ -0,0 +1,115 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	jsmv1beta1 "github.com/artemlive/jsm-operator/api/v1beta1"
+	jsmclient "github.com/artemlive/jsm-operator/internal/client"
+	"github.com/go-logr/logr"
+)
+
+// adoptionPolicy returns the adoption policy of the service. Nothing in JSM
+// tells which cluster created a service, so none is taken over by name unless
+// the spec allows it.
+func (r *JSMServiceReconciler) adoptionPolicy(service *jsmv1beta1.JSMService) jsmv1beta1.AdoptionPolicy {
+	if service.Spec.AdoptionPolicy != "" {
+		return service.Spec.AdoptionPolicy
+	}
+	return jsmv1beta1.AdoptionPolicyNever
+}
+
+// checkAdoption returns why the existing JSM service must not be adopted, or
+// nil if the adoption policy allows it. A service pinned by externalID was
+// chosen explicitly, so only an explicit Never refuses it. One found by the
+// name of an interrupted create is likely ours whatever the policy, but only
+// if no other team responds to it or is linked with it.
+func (r *JSMServiceReconciler) checkAdoption(service *jsmv1beta1.JSMService, teams []jsmv1beta1.ServiceTeamStatus, remote *jsmclient.Service, source jsmv1beta1.AdoptionSource) error {
+	policy := r.adoptionPolicy(service)
+	switch {
+	case source == jsmv1beta1.AdoptionSourcePendingCreate && policy != jsmv1beta1.AdoptionPolicyAlways:
+		if foreign := foreignTeams(remote, teams); len(foreign) > 0 {
+			return fmt.Errorf("JSM service %q (%s) was not left by the interrupted create, it belongs to other teams (%s); set adoptionPolicy to Always to take it over",
+				remote.Name, remote.ID, strings.Join(foreign, ", "))
+		}
+		return nil
+	case source == jsmv1beta1.AdoptionSourceExternalID && service.Spec.AdoptionPolicy == jsmv1beta1.AdoptionPolicyNever:
+		return fmt.Errorf("spec.externalID pins JSM service %s, but adoptionPolicy is Never", remote.ID)
+	case source == jsmv1beta1.AdoptionSourceExternalID:
+		return nil
+	case policy == jsmv1beta1.AdoptionPolicyNever:
+		return fmt.Errorf("JSM service %q already exists as %s and adoptionPolicy is Never", remote.Name, remote.ID)
+	case policy == jsmv1beta1.AdoptionPolicyAlways:
+		return nil
+	}
+
+	if foreign := foreignTeams(remote, teams); len(foreign) > 0 {
+		return fmt.Errorf("JSM service %q (%s) belongs to other teams (%s), set adoptionPolicy to Always to take it over",
+			remote.Name, remote.ID, strings.Join(foreign, ", "))
+	}
+	return nil
+}
+
+// refuseAdoption reports an adoption the policy refused. It stalls the
+// service until the spec changes.
+func (r *JSMServiceReconciler) refuseAdoption(service *jsmv1beta1.JSMService, id string, err error, log logr.Logger) (ctrl.Result, error) {
+	log.Error(err, "Refusing to adopt existing JSMService", "id", id, "adoptionPolicy", r.adoptionPolicy(service))
+	r.setCondition(service, jsmv1beta1.ConditionRemoteSynced, metav1.ConditionFalse, jsmv1beta1.ReasonAdoptionRefused, err.Error())
+	recordEvent(r.Recorder, service, corev1.EventTypeWarning, jsmv1beta1.ReasonAdoptionRefused, opGetServiceByID, id, "%s", err.Error())
+	return ctrl.Result{}, reconcile.TerminalError(err)
+}
+
+// foreignTeams returns the teams that respond to or are linked with the JSM
+// service, but aren't referenced by the spec. A service without such teams
+// is considered unowned. This is a heuristic: JSM keeps no owner of a
+// service, so one created by another cluster for the same teams passes.
+func foreignTeams(remote *jsmclient.Service, teams []jsmv1beta1.ServiceTeamStatus) []string {
+	ours := responderIDs(teams)
+	var foreign []string
+	add := func(teamID string) {
+		if !slices.Contains(ours, teamID) && !slices.Contains(foreign, teamID) {
+			foreign = append(foreign, teamID)
+		}
+	}
+	for _, teamID := range remote.ResponderTeams {
+		add(teamID)
+	}
+	for _, rel := range remote.TeamRelationships {
+		add(rel.TeamID)
+	}
+	return foreign
+}
+
+// forgetService drops everything the status knows about the JSM service, so
+// the next step creates or adopts one from scratch.
+func forgetService(service *jsmv1beta1.JSMService) {
+	service.Status.ID = ""
+	service.Status.Name = ""
+	service.Status.Revision = ""
+	service.Status.TeamRelationshipID = ""
+	service.Status.ResolvedTeamARN = ""
+	service.Status.Teams = nil
+	service.Status.Adoption = nil
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	jsmv1beta1 "github.com/artemlive/jsm-operator/api/v1beta1"
+)
+
+// isSuspended reports whether the suspend annotation pauses the object.
+func isSuspended(obj metav1.Object) bool {
+	return annotationEnabled(obj, jsmv1beta1.SuspendAnnotation)
+}
+
+// isReadOnly reports whether the read-only annotation forbids writing the
+// remote object.
+func isReadOnly(obj metav1.Object) bool {
+	return annotationEnabled(obj, jsmv1beta1.ReadOnlyAnnotation)
+}
+
+// annotationEnabled reports whether the annotation holds a true boolean such
+// as "true". Anything else, including an invalid value, leaves it disabled.
+func annotationEnabled(obj metav1.Object, annotation string) bool {
+	enabled, err := strconv.ParseBool(obj.GetAnnotations()[annotation])
+	return err == nil && enabled
+}
+
+// reconcileRequest returns the value of the reconcile-at annotation and
+// whether it asks for a resync that wasn't done yet.
+func reconcileRequest(obj metav1.Object, lastHandled string) (string, bool) {
+	value := obj.GetAnnotations()[jsmv1beta1.ReconcileAtAnnotation]
+	return value, value != "" && value != lastHandled
+}
+
+// setSuspended reports a suspended object on the Suspended condition. The
+// other conditions keep describing the state it was suspended in.
+func setSuspended(conditions *[]metav1.Condition, generation int64) {
+	setCondition(conditions, generation, jsmv1beta1.ConditionSuspended, metav1.ConditionTrue, jsmv1beta1.ReasonSuspended,
+		"Reconciliation is paused by the "+jsmv1beta1.SuspendAnnotation+" annotation")
+}
+
+// clearSuspended drops the Suspended condition once the object is resumed.
+func clearSuspended(conditions *[]metav1.Condition) {
+	meta.RemoveStatusCondition(conditions, jsmv1beta1.ConditionSuspended)
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	jsmv1beta1 "github.com/artemlive/jsm-operator/api/v1beta1"
+)
+
+// setCondition sets a condition for the given generation. The transition time
+// only changes when the status does.
+func setCondition(conditions *[]metav1.Condition, generation int64, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setReadiness derives the kstatus conditions Ready, Reconciling and Stalled
+// from the outcome of a reconcile and the domain conditions, in the order they
+// are evaluated. The object is Ready only if the reconcile succeeded without an
+// immediate requeue and every domain condition is True for the current
+// generation. Terminal errors stall the object, anything else keeps it
+// Reconciling.
+func setReadiness(conditions *[]metav1.Condition, generation int64, domain []string, result ctrl.Result, err error) {
+	reason, message := readinessBlocker(*conditions, generation, domain, result, err)
+	if reason == "" {
+		setCondition(conditions, generation, jsmv1beta1.ConditionReady, metav1.ConditionTrue, jsmv1beta1.ReasonReconciled, "The remote object matches the spec")
+		setCondition(conditions, generation, jsmv1beta1.ConditionReconciling, metav1.ConditionFalse, jsmv1beta1.ReasonReconciled, "")
+		setCondition(conditions, generation, jsmv1beta1.ConditionStalled, metav1.ConditionFalse, jsmv1beta1.ReasonReconciled, "")
+		return
+	}
+
+	setNotReady(conditions, generation, reason, message, errors.Is(err, reconcile.TerminalError(nil)))
+}
+
+// setNotReady sets Ready to False and either Stalled or Reconciling to True.
+func setNotReady(conditions *[]metav1.Condition, generation int64, reason, message string, stalled bool) {
+	setCondition(conditions, generation, jsmv1beta1.ConditionReady, metav1.ConditionFalse, reason, message)
+	if stalled {
+		setCondition(conditions, generation, jsmv1beta1.ConditionReconciling, metav1.ConditionFalse, reason, "")
+		setCondition(conditions, generation, jsmv1beta1.ConditionStalled, metav1.ConditionTrue, reason, message)
+		return
+	}
+	setCondition(conditions, generation, jsmv1beta1.ConditionReconciling, metav1.ConditionTrue, reason, message)
+	setCondition(conditions, generation, jsmv1beta1.ConditionStalled, metav1.ConditionFalse, reason, "")
+}
+
+// readinessBlocker returns why the object isn't ready, or an empty reason if
+// it is. A domain condition that failed during this reconcile explains the
+// outcome best, then the error, then a domain condition that wasn't reached
+// yet for this generation.
+func readinessBlocker(conditions []metav1.Condition, generation int64, domain []string, result ctrl.Result, err error) (string, string) {
+	for _, conditionType := range domain {
+		c := meta.FindStatusCondition(conditions, conditionType)
+		if c != nil && c.ObservedGeneration == generation && c.Status == metav1.ConditionFalse {
+			return c.Reason, c.Message
+		}
+	}
+	if err != nil {
+		return errorReason(err), err.Error()
+	}
+	for _, conditionType := range domain {
+		c := meta.FindStatusCondition(conditions, conditionType)
+		if c == nil || c.ObservedGeneration != generation || c.Status != metav1.ConditionTrue {
+			return jsmv1beta1.ReasonProgressing, fmt.Sprintf("Waiting for %s", conditionType)
+		}
+	}
+	// a delayed requeue is also used for the periodic resync, only an
+	// immediate one means there is work left
+	if result.Requeue {
+		return jsmv1beta1.ReasonProgressing, "Reconciliation will be retried"
+	}
+	return "", ""
+}
+
+// isReady reports whether the object was Ready for its current generation.
+func isReady(conditions []metav1.Condition, generation int64) bool {
+	c := meta.FindStatusCondition(conditions, jsmv1beta1.ConditionReady)
+	return c != nil && c.Status == metav1.ConditionTrue && c.ObservedGeneration == generation
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	jsmv1beta1 "github.com/artemlive/jsm-operator/api/v1beta1"
+	jsmclient "github.com/artemlive/jsm-operator/internal/client"
+	"github.com/go-logr/logr"
+)
+
+// minResyncDelay keeps a tiny or overdue resync interval from turning into a
+// hot loop.
+const minResyncDelay = time.Second
+
+// resyncInterval returns how often the service is checked for drift. The
+// annotation overrides the operator-wide interval, zero disables the resync.
+func (r *JSMServiceReconciler) resyncInterval(service *jsmv1beta1.JSMService, log logr.Logger) time.Duration {
+	value, ok := service.Annotations[jsmv1beta1.ResyncIntervalAnnotation]
+	if !ok {
+		return r.ResyncInterval
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		log.Info("Ignoring invalid resync interval annotation", "annotation", jsmv1beta1.ResyncIntervalAnnotation, "value", value)
+		return r.ResyncInterval
+	}
+	return interval
+}
+
+// nextResync returns when the service is due for its next drift check, or the
+// zero time if the periodic resync is disabled for it.
+func (r *JSMServiceReconciler) nextResync(service *jsmv1beta1.JSMService, log logr.Logger) time.Time {
+	interval := r.resyncInterval(service, log)
+	if interval <= 0 {
+		return time.Time{}
+	}
+	if service.Status.LastSyncTime == nil {
+		return time.Now()
+	}
+	return service.Status.LastSyncTime.Add(interval)
+}
+
+// markSynced records that the JSM service was just written or compared with
+// the spec.
+func markSynced(service *jsmv1beta1.JSMService) {
+	now := metav1.Now()
+	service.Status.LastSyncTime = &now
+}
+
+// checkDrift compares the JSM service with the spec field by field and
+// records the fields that differ. Depending on the drift policy they are
+// corrected by reapplying the spec or only reported on the Drifted condition.
+func (r *JSMServiceReconciler) checkDrift(ctx context.Context, service *jsmv1beta1.JSMService, teams []jsmv1beta1.ServiceTeamStatus, log logr.Logger) (ctrl.Result, error) {
+	remote, err := r.JSMClient.GetServiceByID(ctx, service.Status.ID)
+	var notFound *jsmclient.NotFoundError
+	if err != nil && !errors.As(err, &notFound) {
+		log.Error(err, "Failed to fetch JSMService for drift detection", "id", service.Status.ID)
+		return r.remoteSyncFailed(service, opGetServiceByID, service.Status.ID, err)
+	}
+
+	tier, err := r.resolveTier(ctx, service)
+	if err != nil {
+		return r.handleTierError(service, err, log)
+	}
+
+	drifted := serviceDrift(service, teams, tier, remote)
+	markSynced(service)
+	service.Status.DriftedFields = drifted
+	if len(drifted) == 0 {
+		service.Status.Revision = remote.Revision
+		r.setCondition(service, jsmv1beta1.ConditionDrifted, metav1.ConditionFalse, jsmv1beta1.ReasonInSync, "The JSM service matches the spec")
+		return ctrl.Result{}, nil
+	}
+
+	reason := jsmv1beta1.ReasonDriftDetected
+	message := fmt.Sprintf("Changed outside of the operator: %s", strings.Join(drifted, ", "))
+	if remote == nil {
+		reason = jsmv1beta1.ReasonRemoteDeleted
+		message = fmt.Sprintf("JSM service %s was deleted outside of the operator", service.Status.ID)
+	}
+
+	// the service type is only set when the service is created
+	correctable := slices.DeleteFunc(slices.Clone(drifted), func(field string) bool { return field == "serviceType" })
+	if r.driftPolicy(service) == jsmv1beta1.DriftPolicyReport || len(correctable) == 0 {
+		log.Info("JSMService drifted from the spec", "id", service.Status.ID, "fields", drifted)
+		if remote != nil && isReadOnly(service) {
+			// the spec may have changed as well, it isn't applied either
+			message = fmt.Sprintf("Differs from the spec, not corrected while read-only: %s", strings.Join(drifted, ", "))
+		}
+		if remote != nil {
+			service.Status.Revision = remote.Revision
+		} else {
+			// nothing backs the resource anymore, so it isn't ready either
+			r.setCondition(service, jsmv1beta1.ConditionRemoteSynced, metav1.ConditionFalse, reason, message)
+		}
+		r.setCondition(service, jsmv1beta1.ConditionDrifted, metav1.ConditionTrue, reason, message)
+		return ctrl.Result{}, nil
+	}
+
+	log.Info("Correcting drift of JSMService", "id", service.Status.ID, "fields", drifted)
+	r.setCondition(service, jsmv1beta1.ConditionDrifted, metav1.ConditionTrue, reason, message)
+
+	var result ctrl.Result
+	if remote == nil {
+		forgetService(service)
+		result, err = r.handleServiceCreation(ctx, service, teams, tier, log)
+	} else {
+		service.Status.Revision = remote.Revision
+		result, err = r.handleServiceUpdate(ctx, service, teams, tier, log)
+	}
+	if err != nil || result.Requeue {
+		return result, err
+	}
+
+	message = fmt.Sprintf("Reapplied the spec to: %s", strings.Join(correctable, ", "))
+	if len(correctable) < len(drifted) {
+		r.setCondition(service, jsmv1beta1.ConditionDrifted, metav1.ConditionTrue, jsmv1beta1.ReasonDriftDetected,
+			message+"; the service type can't be changed after the service was created")
+		return result, nil
+	}
+	r.setCondition(service, jsmv1beta1.ConditionDrifted, metav1.ConditionFalse, jsmv1beta1.ReasonDriftCorrected, message)
+	return result, nil
+}
+
+// remoteDeleted reports whether a resync found the JSM service deleted and
+// only reported it. Such a service is checked for drift again rather than
+// updated, until the policy allows recreating it.
+func remoteDeleted(service *jsmv1beta1.JSMService) bool {
+	c := meta.FindStatusCondition(service.Status.Conditions, jsmv1beta1.ConditionRemoteSynced)
+	return service.Status.ID != "" && c != nil && c.Status == metav1.ConditionFalse && c.Reason == jsmv1beta1.ReasonRemoteDeleted
+}
+
+// driftPolicy returns the drift policy of the service. A read-only service
+// only reports drift.
+func (r *JSMServiceReconciler) driftPolicy(service *jsmv1beta1.JSMService) jsmv1beta1.DriftPolicy {
+	if isReadOnly(service) {
+		return jsmv1beta1.DriftPolicyReport
+	}
+	if service.Spec.DriftPolicy != "" {
+		return service.Spec.DriftPolicy
+	}
+	return jsmv1beta1.DriftPolicyCorrect
+}
+
+// serviceDrift returns the names of the fields in which the JSM service
+// differs from the spec. A service that no longer exists drifted entirely.
+func serviceDrift(service *jsmv1beta1.JSMService, teams []jsmv1beta1.ServiceTeamStatus, tier *jsmclient.Tier, remote *jsmclient.Service) []string {
+	if remote == nil {
+		return []string{"service"}
+	}
+
+	var drifted []string
+	if remote.Name != getServiceName(service) {
+		drifted = append(drifted, "name")
+	}
+	description := ""
+	if remote.Description != nil {
+		description = *remote.Description
+	}
+	if description != service.Spec.Description {
+		drifted = append(drifted, "description")
+	}
+	if remote.TierID != tier.ID {
+		drifted = append(drifted, "tier")
+	}
+	// JSM picks a default type for a service created without one
+	if service.Spec.ServiceTypeKey != "" && remote.ApplicationType != service.Spec.ServiceTypeKey {
+		drifted = append(drifted, "serviceType")
+	}
+
+	if !sameSet(remote.ResponderTeams, responderIDs(teams)) {
+		drifted = append(drifted, "responders")
+	}
+	linked := make([]string, 0, len(remote.TeamRelationships))
+	for _, rel := range remote.TeamRelationships {
+		linked = append(linked, rel.TeamID)
+	}
+	if !sameSet(linked, ownerIDs(teams)) {
+		drifted = append(drifted, "teamRelationships")
+	}
+	return drifted
+}
+
+// updatable reports whether updating the JSM service changes the drifted
+// field. The team relationships are separate objects and the service type is
+// only set when the service is created.
+func updatable(field string) bool {
+	return field != "teamRelationships" && field != "serviceType"
+}
+
+// sameSet reports whether a and b hold the same values, ignoring order and
+// duplicates.
+func sameSet(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(slices.Compact(a), slices.Compact(b))
+}
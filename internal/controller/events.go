@@ -0,0 +1,80 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	jsmv1beta1 "github.com/artemlive/jsm-operator/api/v1beta1"
+)
+
+// Operations of the JSM client, as named on the events about them.
+const (
+	opGetServiceByName               = "GetServiceByName"
+	opGetServiceByID                 = "GetServiceByID"
+	opCreateService                  = "CreateService"
+	opUpdateService                  = "UpdateService"
+	opDeleteService                  = "DeleteService"
+	opGetTierByName                  = "GetTierByName"
+	opGetTierByLevel                 = "GetTierByLevel"
+	opListOpsgenieTeamRelationships  = "ListOpsgenieTeamRelationships"
+	opCreateOpsgenieTeamRelationship = "CreateOpsgenieTeamRelationship"
+	opDeleteOpsgenieTeamRelationship = "DeleteOpsgenieTeamRelationship"
+	opGetOpsgenieTeamIDByName        = "GetOpsgenieTeamIDByName"
+	opGetOpsgenieTeam                = "GetOpsgenieTeam"
+	opCreateOpsgenieTeam             = "CreateOpsgenieTeam"
+	opUpdateOpsgenieTeam             = "UpdateOpsgenieTeam"
+	opDeleteOpsgenieTeam             = "DeleteOpsgenieTeam"
+)
+
+// recordEvent emits an event about a JSM request on obj. The operation and
+// the ARI of the remote object, if known, are added as annotations. Without a
+// recorder the event is dropped.
+func recordEvent(recorder record.EventRecorder, obj runtime.Object, eventType, reason, op, ari, messageFmt string, args ...any) {
+	if recorder == nil {
+		return
+	}
+	annotations := map[string]string{jsmv1beta1.EventAnnotationOperation: op}
+	if ari != "" {
+		annotations[jsmv1beta1.EventAnnotationARI] = ari
+	}
+	recorder.AnnotatedEventf(obj, annotations, eventType, reason, messageFmt, args...)
+}
+
+// recordFailure emits a warning about a failed JSM request.
+func recordFailure(recorder record.EventRecorder, obj runtime.Object, op, ari string, err error) {
+	recordEvent(recorder, obj, corev1.EventTypeWarning, failureReason(op), op, ari, "%s failed: %v", op, err)
+}
+
+// failureReason returns the event reason of a failed operation. Failed
+// lookups of services, tiers and teams are all reported as ResolveFailed.
+func failureReason(op string) string {
+	switch op {
+	case opCreateService, opCreateOpsgenieTeam:
+		return jsmv1beta1.EventCreateFailed
+	case opUpdateService, opUpdateOpsgenieTeam:
+		return jsmv1beta1.EventUpdateFailed
+	case opDeleteService, opDeleteOpsgenieTeam:
+		return jsmv1beta1.ReasonDeleteFailed
+	case opCreateOpsgenieTeamRelationship, opDeleteOpsgenieTeamRelationship:
+		return jsmv1beta1.EventLinkFailed
+	default:
+		return jsmv1beta1.EventResolveFailed
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	jsmv1beta1 "github.com/artemlive/jsm-operator/api/v1beta1"
+	jsmclient "github.com/artemlive/jsm-operator/internal/client"
+)
+
+// jsmErrorResult decides how a reconcile that failed with a JSM API error
+// should be retried:
+//   - rate limited requests are requeued after the delay asked by Atlassian
+//   - invalid input and ambiguous names are terminal, retrying the same
+//     request won't help until the spec changes
+//   - everything else goes through the regular exponential backoff
+func jsmErrorResult(err error) (ctrl.Result, error) {
+	var rateLimited *jsmclient.RateLimitedError
+	if errors.As(err, &rateLimited) && rateLimited.RetryAfter > 0 {
+		return ctrl.Result{RequeueAfter: rateLimited.RetryAfter}, nil
+	}
+
+	if isTerminalJSMError(err) {
+		return ctrl.Result{}, reconcile.TerminalError(err)
+	}
+
+	return ctrl.Result{}, err
+}
+
+// isTerminalJSMError reports whether err can't be fixed by retrying.
+func isTerminalJSMError(err error) bool {
+	var validation *jsmclient.ValidationError
+	var ambiguous *jsmclient.AmbiguousNameError
+	return errors.As(err, &validation) || errors.As(err, &ambiguous)
+}
+
+// errorReason maps an error to the reason reported on the status conditions.
+func errorReason(err error) string {
+	var (
+		conflict    *jsmclient.RevisionConflictError
+		exists      *jsmclient.AlreadyExistsError
+		ambiguous   *jsmclient.AmbiguousNameError
+		validation  *jsmclient.ValidationError
+		notFound    *jsmclient.NotFoundError
+		authErr     *jsmclient.AuthError
+		rateLimited *jsmclient.RateLimitedError
+		serverErr   *jsmclient.ServerError
+	)
+	switch {
+	case errors.As(err, &conflict):
+		return jsmv1beta1.ReasonRevisionConflict
+	case errors.As(err, &exists):
+		return jsmv1beta1.ReasonNameConflict
+	case errors.As(err, &ambiguous):
+		return jsmv1beta1.ReasonAmbiguousName
+	case errors.As(err, &validation):
+		return jsmv1beta1.ReasonInvalidSpec
+	case errors.As(err, &notFound):
+		return jsmv1beta1.ReasonNotFound
+	case errors.As(err, &authErr):
+		return jsmv1beta1.ReasonAuthFailed
+	case errors.As(err, &rateLimited):
+		return jsmv1beta1.ReasonRateLimited
+	case errors.As(err, &serverErr):
+		return jsmv1beta1.ReasonServerError
+	}
+	return jsmv1beta1.ReasonAPIError
+}
@@ -18,12 +18,25 @@ package controller
 
 import (
 	"context"
-
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	jsmv1beta1 "github.com/artemlive/jsm-operator/api/v1beta1"
 	jsmclient "github.com/artemlive/jsm-operator/internal/client"
@@ -34,22 +47,35 @@ import (
 type JSMServiceReconciler struct {
 	client.Client
 	Scheme    *runtime.Scheme
-	JSMClient *jsmclient.JSMClient
+	JSMClient jsmclient.API
+	// DefaultDeletionPolicy applies to services that don't set one, an empty
+	// value means Orphan.
+	DefaultDeletionPolicy jsmv1beta1.DeletionPolicy
+	// ResyncInterval is how often a reconciled service is compared with the
+	// JSM service to detect drift, zero disables it. The resync-interval
+	// annotation overrides it per service.
+	ResyncInterval time.Duration
+	// DryRun plans the changes of every service instead of making them, as
+	// if each set spec.dryRun.
+	DryRun bool
+	// Recorder receives the events about JSM requests, nil drops them.
+	Recorder record.EventRecorder
+	// Queue tunes the workers and backoff of the controller.
+	Queue QueueConfig
 }
 
 // +kubebuilder:rbac:groups=jsm.macpaw.dev,resources=jsmservices,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=jsm.macpaw.dev,resources=jsmservices/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=jsm.macpaw.dev,resources=jsmservices/finalizers,verbs=update
-
-// Reconcile is part of the main kubernetes reconciliation loop which aims to
-// move the current state of the cluster closer to the desired state.
-// TODO(user): Modify the Reconcile function to compare the state specified by
-// the JSMService object against the actual cluster state, and then
-// perform operations to make the cluster state reflect the state specified by
-// the user.
-//
-// For more details, check Reconcile and its Result here:
-// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.20.2/pkg/reconcile
+// +kubebuilder:rbac:groups=jsm.macpaw.dev,resources=jsmteams,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile brings the JSM service of a JSMService in line with its spec. A
+// service being deleted is handed to handleServiceDeletion. Otherwise the
+// finalizer is added and, unless the service is suspended, reconcileService
+// resolves the teams and the tier, then creates, adopts, updates or checks the
+// JSM service for drift. The conditions and the status are written last, and
+// an up-to-date service is requeued for its next resync.
 func (r *JSMServiceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	reconcileLog := log.FromContext(ctx)
 	reconcileLog.Info("Reconciling JSMService", "service", req.NamespacedName)
@@ -59,57 +85,304 @@ func (r *JSMServiceReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	if service.Spec.TeamRef.Name == "" {
-		reconcileLog.Info("No team specified for service, skipping reconciliation", "service", service.Name)
-		return ctrl.Result{}, nil
+	if !service.DeletionTimestamp.IsZero() {
+		return r.handleServiceDeletion(ctx, &service, reconcileLog)
+	}
+
+	if controllerutil.AddFinalizer(&service, jsmv1beta1.ServiceFinalizer) {
+		if err := r.Update(ctx, &service); err != nil {
+			reconcileLog.Error(err, "Failed to add finalizer")
+			return ctrl.Result{}, err
+		}
+	}
+
+	original := service.Status.DeepCopy()
+	if isSuspended(&service) {
+		return r.suspend(ctx, &service, original, reconcileLog)
+	}
+	clearSuspended(&service.Status.Conditions)
+
+	reconcileAt, forced := reconcileRequest(&service, service.Status.LastHandledReconcileAt)
+	result, err := r.reconcileService(ctx, &service, forced, reconcileLog)
+	// a failed resync is retried as a forced one
+	if forced && err == nil {
+		service.Status.LastHandledReconcileAt = reconcileAt
+	}
+	result, err = r.updateStatus(ctx, &service, original, result, err, reconcileLog)
+	if err == nil && result.IsZero() && r.isUpToDate(service) {
+		if next := r.nextResync(&service, reconcileLog); !next.IsZero() {
+			result.RequeueAfter = max(time.Until(next), minResyncDelay)
+		}
+	}
+	return result, err
+}
+
+// serviceConditions are the domain conditions of a JSMService in the order
+// they are reconciled. Ready summarizes them.
+var serviceConditions = []string{
+	jsmv1beta1.ConditionTeamResolved,
+	jsmv1beta1.ConditionTierResolved,
+	jsmv1beta1.ConditionRemoteSynced,
+	jsmv1beta1.ConditionRelationshipLinked,
+}
+
+// reconcileService brings the JSM service in line with the spec. It records
+// its progress in the status of service, which is written by updateStatus.
+// A forced reconcile compares an up-to-date service with JSM right away
+// instead of waiting for the next resync. A read-only service is only
+// compared, never written, and a dry run only plans the changes.
+func (r *JSMServiceReconciler) reconcileService(ctx context.Context, service *jsmv1beta1.JSMService, forced bool, log logr.Logger) (ctrl.Result, error) {
+	// a read-only service is observed rather than planned for
+	readOnly := isReadOnly(service)
+	dryRun := r.isDryRun(service) && !readOnly
+	if !dryRun {
+		service.Status.Plan = nil
+	}
+
+	teams, ok, err := r.resolveTeams(ctx, service, teamRefs(service), log)
+	if !ok {
+		return ctrl.Result{}, err
+	}
+
+	if id := service.Spec.ExternalID; id != "" && service.Status.ID != "" && service.Status.ID != id {
+		log.Info("spec.externalID changed, switching to the pinned JSMService", "oldID", service.Status.ID, "id", id)
+		forgetService(service)
+	}
+
+	if readOnly && service.Status.ID == "" {
+		err := errors.New("read-only, the JSM service is neither created nor adopted")
+		log.Info("JSMService is read-only and has no JSM service to observe", "service", service.Name)
+		r.setCondition(service, jsmv1beta1.ConditionRemoteSynced, metav1.ConditionFalse, jsmv1beta1.ReasonReadOnly, err.Error())
+		return ctrl.Result{}, reconcile.TerminalError(err)
 	}
 
-	team, err := r.getReferencedTeam(ctx, service.Namespace, service.Spec.TeamRef.Name)
+	if r.isUpToDate(*service) && teamsApplied(service, teams) {
+		if forced {
+			log.Info("Resync requested", "annotation", jsmv1beta1.ReconcileAtAnnotation)
+		} else if next := r.nextResync(service, log); next.IsZero() || time.Now().Before(next) {
+			log.Info("Service already exists and is up-to-date", "service", service.Name)
+			return ctrl.Result{}, nil
+		}
+		if !dryRun {
+			return r.checkDrift(ctx, service, teams, log)
+		}
+	} else if readOnly || remoteDeleted(service) && !dryRun {
+		// the spec isn't applied, how it differs shows up as drift
+		return r.checkDrift(ctx, service, teams, log)
+	}
+
+	tier, err := r.resolveTier(ctx, service)
 	if err != nil {
-		reconcileLog.Error(err, "Failed to get referenced JSMTeam", "team", service.Spec.TeamRef.Name)
+		return r.handleTierError(service, err, log)
+	}
+	r.setCondition(service, jsmv1beta1.ConditionTierResolved, metav1.ConditionTrue, jsmv1beta1.ReasonResolved,
+		fmt.Sprintf("Service tier %q (level %d)", tier.Name, tier.Level))
+
+	if dryRun {
+		return r.planChanges(ctx, service, teams, tier, log)
+	}
+
+	if service.Status.ID == "" {
+		return r.handleServiceCreation(ctx, service, teams, tier, log)
+	}
+
+	return r.handleServiceUpdate(ctx, service, teams, tier, log)
+}
+
+// updateStatus sets the kstatus conditions from the outcome of the reconcile
+// and writes the status if anything changed. A failed write is returned
+// instead of the outcome, so the reconcile is retried and nothing, e.g. the ID
+// of a service that was just created, gets lost.
+func (r *JSMServiceReconciler) updateStatus(ctx context.Context, service *jsmv1beta1.JSMService, original *jsmv1beta1.JSMServiceStatus, result ctrl.Result, reconcileErr error, log logr.Logger) (ctrl.Result, error) {
+	setReadiness(&service.Status.Conditions, service.Generation, serviceConditions, result, reconcileErr)
+	service.Status.ObservedGeneration = service.Generation
+
+	if equality.Semantic.DeepEqual(original, &service.Status) {
+		return result, reconcileErr
+	}
+	if err := r.Status().Update(ctx, service); err != nil {
+		log.Error(err, "Failed to update JSMService status")
 		return ctrl.Result{}, err
 	}
+	return result, reconcileErr
+}
 
-	if team.Status.ID == "" {
-		reconcileLog.Info("Referenced JSMTeam has no ID, skipping service creation", "team", team.Name)
+// suspend records that the service is suspended without reconciling it.
+func (r *JSMServiceReconciler) suspend(ctx context.Context, service *jsmv1beta1.JSMService, original *jsmv1beta1.JSMServiceStatus, log logr.Logger) (ctrl.Result, error) {
+	log.Info("JSMService is suspended, skipping", "annotation", jsmv1beta1.SuspendAnnotation)
+	setSuspended(&service.Status.Conditions, service.Generation)
+	if equality.Semantic.DeepEqual(original, &service.Status) {
 		return ctrl.Result{}, nil
 	}
+	if err := r.Status().Update(ctx, service); err != nil {
+		log.Error(err, "Failed to update JSMService status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *JSMServiceReconciler) setCondition(service *jsmv1beta1.JSMService, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	setCondition(&service.Status.Conditions, service.Generation, conditionType, status, reason, message)
+}
 
-	if r.isUpToDate(service) {
-		reconcileLog.Info("Service already exists and is up-to-date", "service", service.Name, "team", team.Name)
+// handleServiceDeletion deletes the JSM service unless the deletion policy
+// orphans it, then releases the finalizer. A failed deletion is reported on
+// the DeletionFailed condition and retried, switching the policy to Orphan
+// releases the resource. A suspended or read-only service is always orphaned.
+func (r *JSMServiceReconciler) handleServiceDeletion(ctx context.Context, service *jsmv1beta1.JSMService, log logr.Logger) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(service, jsmv1beta1.ServiceFinalizer) {
 		return ctrl.Result{}, nil
 	}
 
-	if service.Status.ID == "" {
-		return r.handleServiceCreation(ctx, &service, &team, reconcileLog)
+	policy := r.deletionPolicy(service)
+	if policy == jsmv1beta1.DeletionPolicyDelete && (isSuspended(service) || isReadOnly(service)) {
+		log.Info("Not deleting the JSMService of a suspended or read-only resource", "id", service.Status.ID)
+		policy = jsmv1beta1.DeletionPolicyOrphan
+	}
+	if policy == jsmv1beta1.DeletionPolicyDelete && service.Status.ID != "" && r.isDryRun(service) {
+		log.Info("Dry run, not deleting JSMService", "id", service.Status.ID)
+		recordEvent(r.Recorder, service, corev1.EventTypeNormal, jsmv1beta1.EventPlanned, opDeleteService, service.Status.ID,
+			"Dry run, planned: Delete JSM service %s", service.Status.ID)
+		policy = jsmv1beta1.DeletionPolicyOrphan
+	}
+	if policy == jsmv1beta1.DeletionPolicyDelete && service.Status.ID != "" {
+		err := r.JSMClient.DeleteService(ctx, service.Status.ID)
+		var notFound *jsmclient.NotFoundError
+		if err != nil && !errors.As(err, &notFound) {
+			log.Error(err, "Failed to delete JSMService", "id", service.Status.ID)
+			recordFailure(r.Recorder, service, opDeleteService, service.Status.ID, err)
+			result, resultErr := jsmErrorResult(err)
+			r.setCondition(service, jsmv1beta1.ConditionDeletionFailed, metav1.ConditionTrue, jsmv1beta1.ReasonDeleteFailed, err.Error())
+			setNotReady(&service.Status.Conditions, service.Generation, jsmv1beta1.ReasonDeleteFailed, err.Error(),
+				errors.Is(resultErr, reconcile.TerminalError(nil)))
+			if err := r.Status().Update(ctx, service); err != nil {
+				log.Error(err, "Failed to update status after failed deletion")
+				return ctrl.Result{}, err
+			}
+			return result, resultErr
+		}
+		recordEvent(r.Recorder, service, corev1.EventTypeNormal, jsmv1beta1.EventDeleted, opDeleteService, service.Status.ID,
+			"Deleted JSM service %s", service.Status.ID)
+		log.Info("Deleted JSMService", "id", service.Status.ID)
+	} else if service.Status.ID != "" {
+		log.Info("Leaving JSMService behind", "id", service.Status.ID, "deletionPolicy", policy)
+	}
+
+	controllerutil.RemoveFinalizer(service, jsmv1beta1.ServiceFinalizer)
+	if err := r.Update(ctx, service); err != nil {
+		log.Error(err, "Failed to remove finalizer")
+		return ctrl.Result{}, err
 	}
+	return ctrl.Result{}, nil
+}
 
-	return r.handleServiceUpdate(ctx, &service, &team, reconcileLog)
+func (r *JSMServiceReconciler) deletionPolicy(service *jsmv1beta1.JSMService) jsmv1beta1.DeletionPolicy {
+	if service.Spec.DeletionPolicy != "" {
+		return service.Spec.DeletionPolicy
+	}
+	if r.DefaultDeletionPolicy != "" {
+		return r.DefaultDeletionPolicy
+	}
+	return jsmv1beta1.DeletionPolicyOrphan
 }
 
-func (r *JSMServiceReconciler) getReferencedTeam(ctx context.Context, namespace, name string) (jsmv1beta1.JSMTeam, error) {
-	var team jsmv1beta1.JSMTeam
-	err := r.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, &team)
-	return team, err
+// isDryRun reports whether the changes of the service are only planned.
+func (r *JSMServiceReconciler) isDryRun(service *jsmv1beta1.JSMService) bool {
+	return r.DryRun || service.Spec.DryRun
 }
 
+// isUpToDate reports whether the current generation was already reconciled
+// successfully. The observed generation alone doesn't tell, it is recorded on
+// failures as well.
 func (r *JSMServiceReconciler) isUpToDate(service jsmv1beta1.JSMService) bool {
-	return service.Status.ID != "" && service.Status.ObservedGeneration == service.Generation
+	return service.Status.ID != "" && isReady(service.Status.Conditions, service.Generation)
+}
+
+// resolveTier looks up the service tier requested by the spec, either by name
+// or by level. If both are set they must refer to the same tier.
+func (r *JSMServiceReconciler) resolveTier(ctx context.Context, service *jsmv1beta1.JSMService) (*jsmclient.Tier, error) {
+	spec := service.Spec
+
+	var tier *jsmclient.Tier
+	var err error
+	switch {
+	case spec.TierName != "":
+		tier, err = r.JSMClient.GetTierByName(ctx, spec.TierName)
+	case spec.TierLevel != 0:
+		tier, err = r.JSMClient.GetTierByLevel(ctx, spec.TierLevel)
+	default:
+		return nil, &jsmclient.ValidationError{Message: "either tierLevel or tierName must be set"}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if spec.TierName != "" && spec.TierLevel != 0 && tier.Level != spec.TierLevel {
+		return nil, &jsmclient.ValidationError{
+			Message: fmt.Sprintf("tierName %q is level %d, but tierLevel is %d", spec.TierName, tier.Level, spec.TierLevel),
+		}
+	}
+	return tier, nil
+}
+
+// handleTierError reports a tier that doesn't exist or doesn't match on the
+// TierResolved condition. Such errors are terminal until the spec changes,
+// everything else is retried.
+func (r *JSMServiceReconciler) handleTierError(service *jsmv1beta1.JSMService, err error, log logr.Logger) (ctrl.Result, error) {
+	var notFound *jsmclient.NotFoundError
+	var validation *jsmclient.ValidationError
+	var reason string
+	switch {
+	case errors.As(err, &notFound):
+		reason = jsmv1beta1.ReasonUnknownTier
+	case errors.As(err, &validation):
+		reason = jsmv1beta1.ReasonInvalidTier
+	default:
+		log.Error(err, "Failed to resolve service tier")
+		recordFailure(r.Recorder, service, tierOp(service), service.Status.ID, err)
+		return jsmErrorResult(err)
+	}
+
+	log.Error(err, "Service tier can't be resolved", "tierLevel", service.Spec.TierLevel, "tierName", service.Spec.TierName)
+	recordFailure(r.Recorder, service, tierOp(service), service.Status.ID, err)
+	r.setCondition(service, jsmv1beta1.ConditionTierResolved, metav1.ConditionFalse, reason, err.Error())
+	return ctrl.Result{}, reconcile.TerminalError(err)
 }
 
-func (r *JSMServiceReconciler) handleServiceCreation(ctx context.Context, service *jsmv1beta1.JSMService, team *jsmv1beta1.JSMTeam, log logr.Logger) (ctrl.Result, error) {
+// tierOp returns the operation that resolves the tier of the service.
+func tierOp(service *jsmv1beta1.JSMService) string {
+	if service.Spec.TierName != "" {
+		return opGetTierByName
+	}
+	return opGetTierByLevel
+}
+
+func (r *JSMServiceReconciler) handleServiceCreation(ctx context.Context, service *jsmv1beta1.JSMService, teams []jsmv1beta1.ServiceTeamStatus, tier *jsmclient.Tier, log logr.Logger) (ctrl.Result, error) {
+	if id := service.Spec.ExternalID; id != "" {
+		return r.acquireExistingService(ctx, service, teams, tier, id, jsmv1beta1.AdoptionSourceExternalID, log)
+	}
+
+	if pending := service.Status.PendingCreate; pending != nil {
+		return r.resumeCreate(ctx, service, teams, tier, pending, log)
+	}
+
 	jsmName := getServiceName(service)
 	jsmService, err := r.JSMClient.GetServiceByName(ctx, jsmName)
 	if err != nil {
-		log.Error(err, "Failed to get JSMService by name")
-		return ctrl.Result{}, err
+		var ambiguous *jsmclient.AmbiguousNameError
+		if errors.As(err, &ambiguous) {
+			log.Error(err, "Several JSM services share this name, refusing to pick one", "name", jsmName, "ids", ambiguous.IDs)
+		} else {
+			log.Error(err, "Failed to get JSMService by name")
+		}
+		return r.remoteSyncFailed(service, opGetServiceByName, "", err)
 	}
 
 	if jsmService != nil {
-		return r.acquireExistingService(ctx, service, team, jsmService, log)
+		return r.acquireExistingService(ctx, service, teams, tier, jsmService.ID, jsmv1beta1.AdoptionSourceName, log)
 	}
 
-	return r.createNewService(ctx, service, team, jsmName, log)
+	return r.createNewService(ctx, service, teams, tier, jsmName, log)
 }
 
 func getServiceName(service *jsmv1beta1.JSMService) string {
@@ -119,80 +392,119 @@ func getServiceName(service *jsmv1beta1.JSMService) string {
 	return service.Name
 }
 
-func (r *JSMServiceReconciler) acquireExistingService(ctx context.Context, service *jsmv1beta1.JSMService, team *jsmv1beta1.JSMTeam, jsmService *jsmclient.Service, log logr.Logger) (ctrl.Result, error) {
+// acquireExistingService adopts the JSM service with the given ARI if the
+// adoption policy allows it and applies the spec to it. A refused adoption
+// stalls the resource until the spec changes.
+func (r *JSMServiceReconciler) acquireExistingService(ctx context.Context, service *jsmv1beta1.JSMService, teams []jsmv1beta1.ServiceTeamStatus, tier *jsmclient.Tier, id string, source jsmv1beta1.AdoptionSource, log logr.Logger) (ctrl.Result, error) {
+	// the name lookup only carries the identity, fetch everything else
+	jsmService, err := r.JSMClient.GetServiceByID(ctx, id)
+	if err != nil {
+		log.Error(err, "Failed to fetch existing JSMService", "id", id)
+		result, resultErr := r.remoteSyncFailed(service, opGetServiceByID, id, err)
+		// a pinned service that doesn't exist won't appear by retrying
+		var notFound *jsmclient.NotFoundError
+		if source == jsmv1beta1.AdoptionSourceExternalID && errors.As(err, &notFound) {
+			return ctrl.Result{}, reconcile.TerminalError(err)
+		}
+		return result, resultErr
+	}
+
+	policy := r.adoptionPolicy(service)
+	if err := r.checkAdoption(service, teams, jsmService, source); err != nil {
+		return r.refuseAdoption(service, id, err, log)
+	}
+
 	service.Status.ID = jsmService.ID
+	service.Status.Name = jsmService.Name
 	service.Status.Revision = jsmService.Revision
-	service.Status.ObservedGeneration = service.Generation
 	service.Status.TierID = jsmService.TierID
 	service.Status.TierLevel = jsmService.TierLevel
-	service.Status.ResolvedTeamARN = team.Status.ID
+	service.Status.TierName = jsmService.TierName
+	service.Status.Adoption = &jsmv1beta1.AdoptionStatus{Time: metav1.Now(), Source: source, Policy: policy}
+	markSynced(service)
+	message := fmt.Sprintf("Adopted existing JSM service %s by %s with adoptionPolicy %s", jsmService.ID, strings.ToLower(string(source)), policy)
+	r.setCondition(service, jsmv1beta1.ConditionRemoteSynced, metav1.ConditionTrue, jsmv1beta1.ReasonAdopted, message)
+	recordEvent(r.Recorder, service, corev1.EventTypeNormal, jsmv1beta1.ReasonAdopted, opGetServiceByID, jsmService.ID, "%s", message)
+	log.Info("Acquired existing JSMService", "id", jsmService.ID)
 
-	relationshipID, err := r.ensureTeamRelationship(ctx, service, team)
-	if err != nil {
-		log.Error(err, "Failed to ensure team relationship")
-		return ctrl.Result{}, err
-	}
-	service.Status.TeamRelationshipID = relationshipID
+	return r.applySpecToExisting(ctx, service, teams, tier, jsmService, log)
+}
 
-	if err := r.Status().Update(ctx, service); err != nil {
-		log.Error(err, "Failed to update status after acquiring service")
-		return ctrl.Result{}, err
+// applySpecToExisting brings a JSM service that was just taken over in line
+// with the spec. The relationships alone don't need an update of the service.
+func (r *JSMServiceReconciler) applySpecToExisting(ctx context.Context, service *jsmv1beta1.JSMService, teams []jsmv1beta1.ServiceTeamStatus, tier *jsmclient.Tier, jsmService *jsmclient.Service, log logr.Logger) (ctrl.Result, error) {
+	drifted := slices.DeleteFunc(serviceDrift(service, teams, tier, jsmService), func(field string) bool {
+		return !updatable(field)
+	})
+	if len(drifted) > 0 {
+		log.Info("Applying the spec to the existing JSMService", "id", jsmService.ID, "fields", drifted)
+		return r.handleServiceUpdate(ctx, service, teams, tier, log)
 	}
 
-	log.Info("Acquired existing JSMService", "id", jsmService.ID)
+	if err := r.ensureTeamRelationship(ctx, service, teams); err != nil {
+		log.Error(err, "Failed to ensure team relationship")
+		return jsmErrorResult(err)
+	}
 	return ctrl.Result{}, nil
 }
 
-func (r *JSMServiceReconciler) createNewService(ctx context.Context, service *jsmv1beta1.JSMService, team *jsmv1beta1.JSMTeam, name string, log logr.Logger) (ctrl.Result, error) {
+func (r *JSMServiceReconciler) createNewService(ctx context.Context, service *jsmv1beta1.JSMService, teams []jsmv1beta1.ServiceTeamStatus, tier *jsmclient.Tier, name string, log logr.Logger) (ctrl.Result, error) {
 	serviceReq := jsmclient.CreateServiceRequest{
 		Name:        name,
 		Description: service.Spec.Description,
-		CloudID:     r.JSMClient.CloudID,
-		TierLevel:   service.Spec.TierLevel,
+		TierLevel:   tier.Level,
 		ServiceType: service.Spec.ServiceTypeKey,
-		TeamARNs:    []string{team.Status.ID},
+		TeamARNs:    responderIDs(teams),
+	}
+
+	if err := r.writeAheadCreate(ctx, service, name); err != nil {
+		log.Error(err, "Failed to record the pending create")
+		return ctrl.Result{}, err
 	}
 
 	newService, err := r.JSMClient.CreateService(ctx, &serviceReq)
 	if err != nil {
-		log.Error(err, "Failed to create JSMService")
-		return ctrl.Result{}, err
+		log.Error(err, "Failed to create JSMService", "name", name)
+		if !createMayHaveSucceeded(err) {
+			service.Status.PendingCreate = nil
+		}
+		return r.remoteSyncFailed(service, opCreateService, "", err)
 	}
 
 	service.Status.ID = newService.ID
+	service.Status.Name = name
 	service.Status.Revision = newService.Revision
-	service.Status.ObservedGeneration = service.Generation
 	service.Status.TierID = newService.TierID
-	service.Status.TierLevel = service.Spec.TierLevel
-	service.Status.ResolvedTeamARN = team.Status.ID
-
-	relationshipID, err := r.ensureTeamRelationship(ctx, service, team)
-	if err != nil {
+	service.Status.TierLevel = tier.Level
+	service.Status.TierName = tier.Name
+	service.Status.Adoption = nil
+	service.Status.PendingCreate = nil
+	markSynced(service)
+	r.setCondition(service, jsmv1beta1.ConditionRemoteSynced, metav1.ConditionTrue, jsmv1beta1.ReasonCreated,
+		fmt.Sprintf("Created JSM service %s", newService.ID))
+	recordEvent(r.Recorder, service, corev1.EventTypeNormal, jsmv1beta1.ReasonCreated, opCreateService, newService.ID,
+		"Created JSM service %s", newService.ID)
+
+	if err := r.ensureTeamRelationship(ctx, service, teams); err != nil {
 		log.Error(err, "Failed to create Opsgenie team relationship")
-		return ctrl.Result{}, err
-	}
-	service.Status.TeamRelationshipID = relationshipID
-
-	if err := r.Status().Update(ctx, service); err != nil {
-		log.Error(err, "Failed to update status after create")
-		return ctrl.Result{}, err
+		return jsmErrorResult(err)
 	}
 
 	log.Info("Created new JSMService", "id", newService.ID)
 	return ctrl.Result{}, nil
 }
 
-func (r *JSMServiceReconciler) handleServiceUpdate(ctx context.Context, service *jsmv1beta1.JSMService, team *jsmv1beta1.JSMTeam, log logr.Logger) (ctrl.Result, error) {
+func (r *JSMServiceReconciler) handleServiceUpdate(ctx context.Context, service *jsmv1beta1.JSMService, teams []jsmv1beta1.ServiceTeamStatus, tier *jsmclient.Tier, log logr.Logger) (ctrl.Result, error) {
 	jsmName := getServiceName(service)
 
-	tierID := service.Status.TierID
-	if service.Spec.TierLevel != service.Status.TierLevel {
-		log.Info("Tier level changed, updating service tier", "oldTier", service.Status.TierLevel, "newTier", service.Spec.TierLevel)
-		var err error
-		tierID, err = r.JSMClient.GetTierIDByLevel(ctx, service.Spec.TierLevel)
-		if err != nil {
-			log.Error(err, "Failed to get tier ID by level, using current tier id")
-		}
+	if tier.ID != service.Status.TierID {
+		log.Info("Tier changed, updating service tier", "oldTier", service.Status.TierLevel, "newTier", tier.Level)
+	}
+	// the stored ID identifies the service, so a new name renames it in place
+	renamedFrom := ""
+	if service.Status.Name != "" && service.Status.Name != jsmName {
+		renamedFrom = service.Status.Name
+		log.Info("Name changed, renaming service", "id", service.Status.ID, "oldName", renamedFrom, "newName", jsmName)
 	}
 
 	updateReq := jsmclient.UpdateServiceRequest{
@@ -200,72 +512,185 @@ func (r *JSMServiceReconciler) handleServiceUpdate(ctx context.Context, service
 		Revision:    service.Status.Revision,
 		Name:        jsmName,
 		Description: service.Spec.Description,
-		TierID:      tierID,
+		TierID:      tier.ID,
 		ServiceType: service.Spec.ServiceTypeKey,
-		TeamARNs:    []string{team.Status.ID},
+		TeamARNs:    responderIDs(teams),
 	}
 
 	updSvc, err := r.JSMClient.UpdateService(ctx, &updateReq)
 	if err != nil {
-		if r.JSMClient.IsRevisionConflict(err) {
+		var conflict *jsmclient.RevisionConflictError
+		if errors.As(err, &conflict) {
 			log.Info("Revision conflict detected, refreshing state")
-			latestService, err := r.JSMClient.GetServiceByName(ctx, jsmName)
+			latestService, err := r.JSMClient.GetServiceByID(ctx, service.Status.ID)
 			if err != nil {
 				log.Error(err, "Failed to fetch latest service after conflict")
-				return ctrl.Result{}, err
+				return r.remoteSyncFailed(service, opGetServiceByID, service.Status.ID, err)
 			}
+			recordEvent(r.Recorder, service, corev1.EventTypeNormal, jsmv1beta1.EventConflictRetried, opUpdateService, service.Status.ID,
+				"JSM service %s was changed elsewhere, retrying with revision %s", service.Status.ID, latestService.Revision)
 			service.Status.Revision = latestService.Revision
-			if err := r.Status().Update(ctx, service); err != nil {
-				log.Error(err, "Failed to update status with latest revision")
-				return ctrl.Result{}, err
-			}
+			r.setCondition(service, jsmv1beta1.ConditionRemoteSynced, metav1.ConditionFalse, jsmv1beta1.ReasonRevisionConflict,
+				"The JSM service was changed elsewhere, retrying with the latest revision")
 			return ctrl.Result{Requeue: true}, nil
 		}
+		var exists *jsmclient.AlreadyExistsError
+		if errors.As(err, &exists) {
+			log.Error(err, "Another JSM service already has this name", "id", service.Status.ID, "name", jsmName)
+			recordFailure(r.Recorder, service, opUpdateService, service.Status.ID, err)
+			r.setCondition(service, jsmv1beta1.ConditionRemoteSynced, metav1.ConditionFalse, jsmv1beta1.ReasonNameConflict,
+				fmt.Sprintf("Can't name the JSM service %q, another service already has this name", jsmName))
+			return ctrl.Result{}, reconcile.TerminalError(err)
+		}
 		log.Error(err, "Failed to update JSMService")
-		return ctrl.Result{}, err
+		return r.remoteSyncFailed(service, opUpdateService, service.Status.ID, err)
 	}
 
+	service.Status.Name = jsmName
 	service.Status.Revision = updSvc.Revision
-	service.Status.ObservedGeneration = service.Generation
 	service.Status.TierID = updSvc.TierID
 	service.Status.TierLevel = updSvc.TierLevel
-
-	if service.Status.ResolvedTeamARN != team.Status.ID {
-		log.Info("Team has changed, updating team relationship")
-		relationshipID, err := r.ensureTeamRelationship(ctx, service, team)
-		if err != nil {
-			log.Error(err, "Failed to update Opsgenie team relationship")
-			return ctrl.Result{}, err
-		}
-		service.Status.TeamRelationshipID = relationshipID
-		service.Status.ResolvedTeamARN = team.Status.ID
+	service.Status.TierName = tier.Name
+	markSynced(service)
+	recordEvent(r.Recorder, service, corev1.EventTypeNormal, jsmv1beta1.EventUpdated, opUpdateService, service.Status.ID,
+		"Updated JSM service %s to revision %s", service.Status.ID, updSvc.Revision)
+	if renamedFrom != "" {
+		recordEvent(r.Recorder, service, corev1.EventTypeNormal, jsmv1beta1.ReasonRenamed, opUpdateService, service.Status.ID,
+			"Renamed JSM service %s from %q to %q", service.Status.ID, renamedFrom, jsmName)
 	}
+	r.setCondition(service, jsmv1beta1.ConditionRemoteSynced, metav1.ConditionTrue, jsmv1beta1.ReasonSynced,
+		fmt.Sprintf("Updated JSM service to revision %s", updSvc.Revision))
 
-	if err := r.Status().Update(ctx, service); err != nil {
-		log.Error(err, "Failed to update status after update")
-		return ctrl.Result{}, err
+	if !teamsApplied(service, teams) {
+		log.Info("Teams have changed, updating team relationships", "oldTeams", responderIDs(service.Status.Teams), "newTeams", responderIDs(teams))
+	}
+	if err := r.ensureTeamRelationship(ctx, service, teams); err != nil {
+		log.Error(err, "Failed to update Opsgenie team relationship")
+		return jsmErrorResult(err)
 	}
 
 	log.Info("Updated JSMService successfully", "id", service.Status.ID)
 	return ctrl.Result{}, nil
 }
 
-func (r *JSMServiceReconciler) ensureTeamRelationship(ctx context.Context, service *jsmv1beta1.JSMService, team *jsmv1beta1.JSMTeam) (string, error) {
-	relationshipID, err := r.JSMClient.CreateOpsgenieTeamRelationship(ctx, service.Status.ID, team.Status.ID)
+// remoteSyncFailed reports a failed JSM request on the RemoteSynced condition
+// and by a warning event. id is the ARI of the service the request was about,
+// if known.
+func (r *JSMServiceReconciler) remoteSyncFailed(service *jsmv1beta1.JSMService, op, id string, err error) (ctrl.Result, error) {
+	r.setCondition(service, jsmv1beta1.ConditionRemoteSynced, metav1.ConditionFalse, errorReason(err), err.Error())
+	recordFailure(r.Recorder, service, op, id, err)
+	return jsmErrorResult(err)
+}
+
+// ensureTeamRelationship links the service with exactly the owner teams and
+// records the teams with their relationships in the status and on the
+// RelationshipLinked condition.
+func (r *JSMServiceReconciler) ensureTeamRelationship(ctx context.Context, service *jsmv1beta1.JSMService, teams []jsmv1beta1.ServiceTeamStatus) error {
+	owners := ownerIDs(teams)
+	linked, err := r.syncTeamRelationships(ctx, service, owners)
 	if err != nil {
-		return "", err
+		r.setCondition(service, jsmv1beta1.ConditionRelationshipLinked, metav1.ConditionFalse, errorReason(err), err.Error())
+		return err
+	}
+
+	service.Status.Teams = nil
+	for _, team := range teams {
+		if team.Role == jsmv1beta1.TeamRoleOwner {
+			team.RelationshipID = linked[team.ID]
+		}
+		service.Status.Teams = append(service.Status.Teams, team)
+	}
+	service.Status.ResolvedTeamARN, service.Status.TeamRelationshipID = "", ""
+	if len(owners) > 0 {
+		service.Status.ResolvedTeamARN = owners[0]
+		service.Status.TeamRelationshipID = linked[owners[0]]
 	}
 
-	service.Status.TeamRelationshipID = relationshipID
-	service.Status.ResolvedTeamARN = team.Status.ID
-	return relationshipID, nil
+	r.setCondition(service, jsmv1beta1.ConditionRelationshipLinked, metav1.ConditionTrue, jsmv1beta1.ReasonLinked, linkedMessage(owners))
+	return nil
+}
+
+// linkedMessage is the message of the RelationshipLinked condition.
+func linkedMessage(owners []string) string {
+	if len(owners) == 0 {
+		return "No owner team to link with"
+	}
+	return fmt.Sprintf("Linked with Opsgenie teams %s", strings.Join(owners, ", "))
+}
+
+// syncTeamRelationships converges the Opsgenie team relationships of a
+// service to exactly the given teams: missing links are created first, then
+// links to any other team are deleted. It returns the relationship ID per
+// team, an ID is empty if the link was reported to exist but not listed yet.
+// Every change and failure is recorded as an event.
+func (r *JSMServiceReconciler) syncTeamRelationships(ctx context.Context, service *jsmv1beta1.JSMService, teamIDs []string) (map[string]string, error) {
+	serviceID := service.Status.ID
+	existing, err := r.JSMClient.ListOpsgenieTeamRelationships(ctx, serviceID)
+	if err != nil {
+		recordFailure(r.Recorder, service, opListOpsgenieTeamRelationships, serviceID, err)
+		return nil, err
+	}
+
+	desired := make(map[string]bool, len(teamIDs))
+	for _, teamID := range teamIDs {
+		desired[teamID] = true
+	}
+	linked := make(map[string]string, len(teamIDs))
+	var stale []jsmclient.TeamRelationship
+	for _, rel := range existing {
+		if desired[rel.TeamID] {
+			linked[rel.TeamID] = rel.ID
+			continue
+		}
+		stale = append(stale, rel)
+	}
+
+	for _, teamID := range teamIDs {
+		if _, ok := linked[teamID]; ok {
+			continue
+		}
+		relationshipID, err := r.JSMClient.CreateOpsgenieTeamRelationship(ctx, serviceID, teamID)
+		var exists *jsmclient.AlreadyExistsError
+		if err != nil && !errors.As(err, &exists) {
+			recordFailure(r.Recorder, service, opCreateOpsgenieTeamRelationship, serviceID, err)
+			return nil, err
+		}
+		if err == nil {
+			recordEvent(r.Recorder, service, corev1.EventTypeNormal, jsmv1beta1.EventTeamLinked, opCreateOpsgenieTeamRelationship, serviceID,
+				"Linked JSM service %s with Opsgenie team %s by relationship %s", serviceID, teamID, relationshipID)
+		}
+		linked[teamID] = relationshipID
+	}
+
+	for _, rel := range stale {
+		err := r.JSMClient.DeleteOpsgenieTeamRelationship(ctx, rel.ID)
+		var notFound *jsmclient.NotFoundError
+		if err != nil && !errors.As(err, &notFound) {
+			recordFailure(r.Recorder, service, opDeleteOpsgenieTeamRelationship, serviceID, err)
+			return nil, err
+		}
+		recordEvent(r.Recorder, service, corev1.EventTypeNormal, jsmv1beta1.EventTeamUnlinked, opDeleteOpsgenieTeamRelationship, serviceID,
+			"Unlinked JSM service %s from Opsgenie team %s", serviceID, rel.TeamID)
+	}
+
+	return linked, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *JSMServiceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &jsmv1beta1.JSMService{}, teamRefIndex, indexTeamRefs); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&jsmv1beta1.JSMService{}).
-		WithEventFilter(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{})).
+		WithOptions(r.Queue.options(DefaultServiceBaseBackoff, DefaultServiceMaxBackoff)).
+		For(&jsmv1beta1.JSMService{}, builder.WithPredicates(
+			predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}),
+		)).
+		Watches(&jsmv1beta1.JSMTeam{},
+			handler.EnqueueRequestsFromMapFunc(r.servicesForTeam),
+			builder.WithPredicates(teamChanged),
+		).
 		Named("jsmservice").
 		Complete(r)
 }
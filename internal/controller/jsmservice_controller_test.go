@@ -18,16 +18,27 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	jsmv1beta1 "github.com/artemlive/jsm-operator/api/v1beta1"
+	"github.com/artemlive/jsm-operator/internal/client/fake"
 )
 
 var _ = Describe("JSMService Controller", func() {
@@ -51,7 +62,7 @@ var _ = Describe("JSMService Controller", func() {
 						Name:      resourceName,
 						Namespace: "default",
 					},
-					// TODO(user): Specify other spec details if needed.
+					Spec: jsmv1beta1.JSMServiceSpec{TierLevel: 3},
 				}
 				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
 			}
@@ -69,8 +80,10 @@ var _ = Describe("JSMService Controller", func() {
 		It("should successfully reconcile the resource", func() {
 			By("Reconciling the created resource")
 			controllerReconciler := &JSMServiceReconciler{
-				Client: k8sClient,
-				Scheme: k8sClient.Scheme(),
+				Client:    k8sClient,
+				Scheme:    k8sClient.Scheme(),
+				JSMClient: jsmClient,
+				Recorder:  record.NewFakeRecorder(10),
 			}
 
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -82,3 +95,1103 @@ var _ = Describe("JSMService Controller", func() {
 		})
 	})
 })
+
+// failingStatusClient fails its failAt-th status update, like a reconcile
+// that dies or loses a conflict at that point.
+type failingStatusClient struct {
+	client.Client
+	failAt int
+	calls  int
+}
+
+func (c *failingStatusClient) Status() client.SubResourceWriter {
+	return &failingStatusWriter{SubResourceWriter: c.Client.Status(), client: c}
+}
+
+type failingStatusWriter struct {
+	client.SubResourceWriter
+	client *failingStatusClient
+}
+
+func (w *failingStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	w.client.calls++
+	if w.client.calls == w.client.failAt {
+		return errors.NewConflict(jsmv1beta1.GroupVersion.WithResource("jsmservices").GroupResource(), obj.GetName(), fmt.Errorf("the object has been modified"))
+	}
+	return w.SubResourceWriter.Update(ctx, obj, opts...)
+}
+
+// expectReady asserts the kstatus conditions of a fully reconciled object.
+func expectReady(conditions []metav1.Condition, generation int64) {
+	GinkgoHelper()
+	for conditionType, status := range map[string]metav1.ConditionStatus{
+		jsmv1beta1.ConditionReady:       metav1.ConditionTrue,
+		jsmv1beta1.ConditionReconciling: metav1.ConditionFalse,
+		jsmv1beta1.ConditionStalled:     metav1.ConditionFalse,
+	} {
+		condition := meta.FindStatusCondition(conditions, conditionType)
+		Expect(condition).NotTo(BeNil(), conditionType)
+		Expect(condition.Status).To(Equal(status), conditionType)
+		Expect(condition.ObservedGeneration).To(Equal(generation), conditionType)
+	}
+}
+
+var _ = Describe("JSMService Controller against the fake JSM backend", func() {
+	const namespace = "default"
+
+	var (
+		ctx        context.Context
+		reconciler *JSMServiceReconciler
+		recorder   *record.FakeRecorder
+	)
+
+	// createTeamIn creates a JSMTeam pinned to a team of the fake backend and
+	// reconciles it, so that its status carries the team ARI.
+	createTeamIn := func(teamNamespace, name string, allowedNamespaces ...string) string {
+		teamID := jsmServer.AddTeam(name)
+		team := &jsmv1beta1.JSMTeam{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: teamNamespace},
+			Spec:       jsmv1beta1.JSMTeamSpec{Name: name, ID: teamID, AllowedNamespaces: allowedNamespaces},
+		}
+		Expect(k8sClient.Create(ctx, team)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, team)
+
+		teamReconciler := &JSMTeamReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), JSMClient: jsmClient, Recorder: record.NewFakeRecorder(10)}
+		_, err := teamReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(team)})
+		Expect(err).NotTo(HaveOccurred())
+		return teamID
+	}
+
+	createTeam := func(name string) string {
+		return createTeamIn(namespace, name)
+	}
+
+	reconcileService := func(key types.NamespacedName) (reconcile.Result, *jsmv1beta1.JSMService) {
+		result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		service := &jsmv1beta1.JSMService{}
+		Expect(k8sClient.Get(ctx, key, service)).To(Succeed())
+		return result, service
+	}
+
+	// recordedEvents drains the events recorded so far.
+	recordedEvents := func() []string {
+		var events []string
+		for {
+			select {
+			case event := <-recorder.Events:
+				events = append(events, event)
+			default:
+				return events
+			}
+		}
+	}
+
+	updateSpec := func(key types.NamespacedName, mutate func(*jsmv1beta1.JSMServiceSpec)) {
+		service := &jsmv1beta1.JSMService{}
+		Expect(k8sClient.Get(ctx, key, service)).To(Succeed())
+		mutate(&service.Spec)
+		Expect(k8sClient.Update(ctx, service)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		recorder = record.NewFakeRecorder(100)
+		reconciler = &JSMServiceReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), JSMClient: jsmClient, Recorder: recorder}
+	})
+
+	It("creates, updates, refreshes on conflict and relinks teams", func() {
+		sreID := createTeam("flow-sre")
+		key := types.NamespacedName{Name: "flow-api", Namespace: namespace}
+		service := &jsmv1beta1.JSMService{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: namespace},
+			Spec: jsmv1beta1.JSMServiceSpec{
+				Description:    "public api",
+				TierLevel:      2,
+				ServiceTypeKey: "APPLICATIONS",
+				TeamRef:        &jsmv1beta1.JSMTeamRef{Name: "flow-sre"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, service)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, service)
+
+		By("creating the remote service")
+		_, service = reconcileService(key)
+		Expect(service.Status.ID).NotTo(BeEmpty())
+		expectReady(service.Status.Conditions, service.Generation)
+		for _, conditionType := range []string{
+			jsmv1beta1.ConditionTeamResolved,
+			jsmv1beta1.ConditionTierResolved,
+			jsmv1beta1.ConditionRemoteSynced,
+			jsmv1beta1.ConditionRelationshipLinked,
+		} {
+			condition := meta.FindStatusCondition(service.Status.Conditions, conditionType)
+			Expect(condition).NotTo(BeNil(), conditionType)
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue), conditionType)
+			Expect(condition.ObservedGeneration).To(Equal(service.Generation), conditionType)
+		}
+		remote, ok := jsmServer.Service(service.Status.ID)
+		Expect(ok).To(BeTrue())
+		Expect(remote.Name).To(Equal("flow-api"))
+		Expect(remote.ResponderTeams).To(ConsistOf(sreID))
+		Expect(jsmServer.Relationships(service.Status.ID)).To(HaveLen(1))
+
+		By("pushing spec changes")
+		updateSpec(key, func(spec *jsmv1beta1.JSMServiceSpec) {
+			spec.Description = "still public"
+			spec.TierLevel = 1
+		})
+		_, service = reconcileService(key)
+		remote, _ = jsmServer.Service(service.Status.ID)
+		Expect(remote.Description).To(Equal("still public"))
+		Expect(remote.TierLevel).To(Equal(1))
+		Expect(service.Status.Revision).To(Equal(remote.Revision))
+
+		By("refreshing the revision after a change made in the JSM UI")
+		jsmServer.EditService(service.Status.ID, func(s *fake.Service) { s.Description = "edited in UI" })
+		updateSpec(key, func(spec *jsmv1beta1.JSMServiceSpec) {
+			spec.Description = "back to spec"
+		})
+		result, service := reconcileService(key)
+		Expect(result.Requeue).To(BeTrue())
+		remote, _ = jsmServer.Service(service.Status.ID)
+		Expect(service.Status.Revision).To(Equal(remote.Revision))
+		Expect(meta.IsStatusConditionTrue(service.Status.Conditions, jsmv1beta1.ConditionReconciling)).To(BeTrue())
+		Expect(meta.FindStatusCondition(service.Status.Conditions, jsmv1beta1.ConditionReady).Reason).
+			To(Equal(jsmv1beta1.ReasonRevisionConflict))
+		_, service = reconcileService(key)
+		remote, _ = jsmServer.Service(service.Status.ID)
+		Expect(remote.Description).To(Equal("back to spec"))
+		expectReady(service.Status.Conditions, service.Generation)
+
+		By("linking a new team")
+		coreID := createTeam("flow-core")
+		updateSpec(key, func(spec *jsmv1beta1.JSMServiceSpec) {
+			spec.TeamRef = &jsmv1beta1.JSMTeamRef{Name: "flow-core"}
+		})
+		_, service = reconcileService(key)
+		Expect(service.Status.ResolvedTeamARN).To(Equal(coreID))
+		remote, _ = jsmServer.Service(service.Status.ID)
+		Expect(remote.ResponderTeams).To(ConsistOf(coreID))
+		relationships := jsmServer.Relationships(service.Status.ID)
+		Expect(relationships).To(HaveLen(1))
+		Expect(relationships[0].TeamID).To(Equal(coreID))
+		Expect(service.Status.TeamRelationshipID).To(Equal(relationships[0].ID))
+	})
+
+	It("manages a service without responder teams", func() {
+		sreID := createTeam("teamless-sre")
+		key := types.NamespacedName{Name: "teamless-api", Namespace: namespace}
+		service := &jsmv1beta1.JSMService{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: namespace},
+			Spec: jsmv1beta1.JSMServiceSpec{
+				TierLevel:      3,
+				ServiceTypeKey: "BUSINESS_SERVICES",
+			},
+		}
+		Expect(k8sClient.Create(ctx, service)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, service)
+
+		By("creating it with empty responders")
+		_, service = reconcileService(key)
+		Expect(service.Status.ID).NotTo(BeEmpty())
+		expectReady(service.Status.Conditions, service.Generation)
+		Expect(meta.FindStatusCondition(service.Status.Conditions, jsmv1beta1.ConditionTeamResolved).Reason).
+			To(Equal(jsmv1beta1.ReasonNoTeamRef))
+		remote, _ := jsmServer.Service(service.Status.ID)
+		Expect(remote.ResponderTeams).To(BeEmpty())
+		Expect(jsmServer.Relationships(service.Status.ID)).To(BeEmpty())
+
+		By("adding a team later")
+		updateSpec(key, func(spec *jsmv1beta1.JSMServiceSpec) {
+			spec.TeamRef = &jsmv1beta1.JSMTeamRef{Name: "teamless-sre"}
+		})
+		_, service = reconcileService(key)
+		remote, _ = jsmServer.Service(service.Status.ID)
+		Expect(remote.ResponderTeams).To(ConsistOf(sreID))
+		Expect(jsmServer.Relationships(service.Status.ID)).To(HaveLen(1))
+
+		By("removing the responders and relationships with the team")
+		updateSpec(key, func(spec *jsmv1beta1.JSMServiceSpec) {
+			spec.TeamRef = nil
+		})
+		_, service = reconcileService(key)
+		expectReady(service.Status.Conditions, service.Generation)
+		remote, _ = jsmServer.Service(service.Status.ID)
+		Expect(remote.ResponderTeams).To(BeEmpty())
+		Expect(jsmServer.Relationships(service.Status.ID)).To(BeEmpty())
+		Expect(service.Status.Teams).To(BeEmpty())
+		Expect(service.Status.ResolvedTeamARN).To(BeEmpty())
+		Expect(service.Status.TeamRelationshipID).To(BeEmpty())
+	})
+
+	It("links owners and responders from other namespaces", func() {
+		const teamsNamespace = "teams"
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: teamsNamespace}}
+		Expect(client.IgnoreAlreadyExists(k8sClient.Create(ctx, ns))).To(Succeed())
+		ownerID := createTeam("multi-owner")
+		platformID := createTeamIn(teamsNamespace, "multi-platform", namespace)
+		privateID := createTeamIn(teamsNamespace, "multi-private")
+
+		key := types.NamespacedName{Name: "multi-api", Namespace: namespace}
+		service := &jsmv1beta1.JSMService{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: namespace},
+			Spec: jsmv1beta1.JSMServiceSpec{
+				TierLevel: 2,
+				TeamRefs: []jsmv1beta1.JSMTeamRef{
+					{Name: "multi-owner"},
+					{Name: "multi-platform", Namespace: teamsNamespace, Role: jsmv1beta1.TeamRoleResponder},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, service)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, service)
+
+		By("making every team a responder and linking only the owner")
+		_, service = reconcileService(key)
+		expectReady(service.Status.Conditions, service.Generation)
+		remote, _ := jsmServer.Service(service.Status.ID)
+		Expect(remote.ResponderTeams).To(ConsistOf(ownerID, platformID))
+		relationships := jsmServer.Relationships(service.Status.ID)
+		Expect(relationships).To(HaveLen(1))
+		Expect(relationships[0].TeamID).To(Equal(ownerID))
+		Expect(service.Status.Teams).To(Equal([]jsmv1beta1.ServiceTeamStatus{
+			{Name: "multi-owner", Namespace: namespace, Role: jsmv1beta1.TeamRoleOwner, ID: ownerID, RelationshipID: relationships[0].ID},
+			{Name: "multi-platform", Namespace: teamsNamespace, Role: jsmv1beta1.TeamRoleResponder, ID: platformID},
+		}))
+		Expect(service.Status.ResolvedTeamARN).To(Equal(ownerID))
+
+		By("promoting the responder to a second owner")
+		updateSpec(key, func(spec *jsmv1beta1.JSMServiceSpec) {
+			spec.TeamRefs[1].Role = jsmv1beta1.TeamRoleOwner
+		})
+		_, service = reconcileService(key)
+		expectReady(service.Status.Conditions, service.Generation)
+		Expect(jsmServer.Relationships(service.Status.ID)).To(HaveLen(2))
+		Expect(service.Status.Teams[1].RelationshipID).NotTo(BeEmpty())
+
+		By("refusing a team that doesn't allow the namespace of the service")
+		updateSpec(key, func(spec *jsmv1beta1.JSMServiceSpec) {
+			spec.TeamRefs = append(spec.TeamRefs, jsmv1beta1.JSMTeamRef{Name: "multi-private", Namespace: teamsNamespace})
+		})
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).To(MatchError(reconcile.TerminalError(nil)))
+		Expect(k8sClient.Get(ctx, key, service)).To(Succeed())
+		condition := meta.FindStatusCondition(service.Status.Conditions, jsmv1beta1.ConditionTeamResolved)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(jsmv1beta1.ReasonTeamRefNotAllowed))
+		Expect(meta.IsStatusConditionTrue(service.Status.Conditions, jsmv1beta1.ConditionStalled)).To(BeTrue())
+		remote, _ = jsmServer.Service(service.Status.ID)
+		Expect(remote.ResponderTeams).NotTo(ContainElement(privateID))
+	})
+
+	It("adopts an existing service with the exact same name", func() {
+		sreID := createTeam("adopt-sre")
+		staleID := jsmServer.AddTeam("adopt-stale")
+		jsmServer.AddService(fake.Service{Name: "adopt-api-legacy", TierLevel: 3})
+		existing := jsmServer.AddService(fake.Service{Name: "adopt-api", TierLevel: 3})
+		linkedID, err := jsmClient.CreateOpsgenieTeamRelationship(ctx, existing.ID, sreID)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = jsmClient.CreateOpsgenieTeamRelationship(ctx, existing.ID, staleID)
+		Expect(err).NotTo(HaveOccurred())
+
+		key := types.NamespacedName{Name: "adopt-api", Namespace: namespace}
+		service := &jsmv1beta1.JSMService{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: namespace},
+			Spec: jsmv1beta1.JSMServiceSpec{
+				TierLevel:      3,
+				TeamRef:        &jsmv1beta1.JSMTeamRef{Name: "adopt-sre"},
+				AdoptionPolicy: jsmv1beta1.AdoptionPolicyIfUnowned,
+			},
+		}
+		Expect(k8sClient.Create(ctx, service)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, service)
+		creates := jsmServer.Calls("createDevOpsService")
+
+		By("refusing to take over a service linked with another team")
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).To(MatchError(reconcile.TerminalError(nil)))
+		Expect(err).To(MatchError(ContainSubstring(staleID)))
+		Expect(k8sClient.Get(ctx, key, service)).To(Succeed())
+		Expect(service.Status.ID).To(BeEmpty())
+		Expect(meta.FindStatusCondition(service.Status.Conditions, jsmv1beta1.ConditionRemoteSynced).Reason).
+			To(Equal(jsmv1beta1.ReasonAdoptionRefused))
+		Expect(meta.IsStatusConditionTrue(service.Status.Conditions, jsmv1beta1.ConditionStalled)).To(BeTrue())
+		Expect(recordedEvents()).To(ContainElement(HavePrefix("Warning AdoptionRefused")))
+
+		By("adopting it with the Always policy")
+		updateSpec(key, func(spec *jsmv1beta1.JSMServiceSpec) {
+			spec.AdoptionPolicy = jsmv1beta1.AdoptionPolicyAlways
+		})
+		_, service = reconcileService(key)
+		Expect(service.Status.ID).To(Equal(existing.ID))
+		Expect(service.Status.Adoption).NotTo(BeNil())
+		Expect(service.Status.Adoption.Source).To(Equal(jsmv1beta1.AdoptionSourceName))
+		Expect(service.Status.Adoption.Policy).To(Equal(jsmv1beta1.AdoptionPolicyAlways))
+		Expect(service.Status.Adoption.Time.IsZero()).To(BeFalse())
+		Expect(recordedEvents()).To(ContainElement(HavePrefix("Normal Adopted")))
+		Expect(service.Status.TierID).To(Equal(existing.TierID))
+		Expect(service.Status.TierLevel).To(Equal(3))
+		Expect(service.Status.TierName).To(Equal("Medium"))
+		Expect(jsmServer.Calls("createDevOpsService")).To(Equal(creates))
+
+		By("keeping the existing link and dropping the stale one")
+		Expect(service.Status.TeamRelationshipID).To(Equal(linkedID))
+		Expect(jsmServer.Relationships(existing.ID)).To(ConsistOf(fake.Relationship{
+			ID: linkedID, ServiceID: existing.ID, TeamID: sreID,
+		}))
+	})
+
+	It("adopts only what the adoption policy allows", func() {
+		sreID := createTeam("policy-sre")
+		newService := func(name string, spec jsmv1beta1.JSMServiceSpec) types.NamespacedName {
+			key := types.NamespacedName{Name: name, Namespace: namespace}
+			spec.TierLevel = 3
+			spec.TeamRef = &jsmv1beta1.JSMTeamRef{Name: "policy-sre"}
+			service := &jsmv1beta1.JSMService{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}, Spec: spec}
+			Expect(k8sClient.Create(ctx, service)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, service)
+			return key
+		}
+
+		By("never adopting by name by default")
+		jsmServer.AddService(fake.Service{Name: "policy-never", TierLevel: 3})
+		key := newService("policy-never", jsmv1beta1.JSMServiceSpec{})
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).To(MatchError(reconcile.TerminalError(nil)))
+		Expect(err).To(MatchError(ContainSubstring("adoptionPolicy is Never")))
+		service := &jsmv1beta1.JSMService{}
+		Expect(k8sClient.Get(ctx, key, service)).To(Succeed())
+		Expect(service.Status.ID).To(BeEmpty())
+		Expect(service.Status.Adoption).To(BeNil())
+
+		By("adopting an unowned service with the IfUnowned policy")
+		unowned := jsmServer.AddService(fake.Service{Name: "policy-unowned", TierLevel: 3})
+		_, service = reconcileService(newService("policy-unowned", jsmv1beta1.JSMServiceSpec{AdoptionPolicy: jsmv1beta1.AdoptionPolicyIfUnowned}))
+		Expect(service.Status.ID).To(Equal(unowned.ID))
+		Expect(service.Status.Adoption.Policy).To(Equal(jsmv1beta1.AdoptionPolicyIfUnowned))
+
+		By("adopting a service that only our team responds to")
+		ours := jsmServer.AddService(fake.Service{Name: "policy-ours", TierLevel: 3, ResponderTeams: []string{sreID}})
+		_, service = reconcileService(newService("policy-ours", jsmv1beta1.JSMServiceSpec{AdoptionPolicy: jsmv1beta1.AdoptionPolicyIfUnowned}))
+		Expect(service.Status.ID).To(Equal(ours.ID))
+
+		By("adopting the service pinned by externalID whatever its name")
+		foreignID := jsmServer.AddTeam("policy-foreign")
+		pinned := jsmServer.AddService(fake.Service{Name: "Legacy Checkout", TierLevel: 3, ResponderTeams: []string{foreignID}})
+		key = newService("policy-pinned", jsmv1beta1.JSMServiceSpec{ExternalID: pinned.ID})
+		_, service = reconcileService(key)
+		Expect(service.Status.ID).To(Equal(pinned.ID))
+		Expect(service.Status.Adoption.Source).To(Equal(jsmv1beta1.AdoptionSourceExternalID))
+		_, service = reconcileService(key)
+		remote, _ := jsmServer.Service(pinned.ID)
+		Expect(remote.Name).To(Equal("policy-pinned"))
+		Expect(remote.ResponderTeams).To(ConsistOf(sreID))
+
+		By("stalling on a pinned service that doesn't exist")
+		key = newService("policy-missing", jsmv1beta1.JSMServiceSpec{ExternalID: "ari:cloud:graph::service/test-cloud/missing"})
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).To(MatchError(reconcile.TerminalError(nil)))
+	})
+
+	It("never creates a duplicate when a create is interrupted", func() {
+		createTeam("crash-sre")
+		newService := func(name string) types.NamespacedName {
+			key := types.NamespacedName{Name: name, Namespace: namespace}
+			service := &jsmv1beta1.JSMService{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+				Spec: jsmv1beta1.JSMServiceSpec{
+					TierLevel: 2,
+					TeamRef:   &jsmv1beta1.JSMTeamRef{Name: "crash-sre"},
+					// the service of an interrupted create is ours, not adopted
+					AdoptionPolicy: jsmv1beta1.AdoptionPolicyNever,
+				},
+			}
+			Expect(k8sClient.Create(ctx, service)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, service)
+			return key
+		}
+		servicesNamed := func(name string) []fake.Service {
+			return slices.DeleteFunc(jsmServer.Services(), func(s fake.Service) bool { return s.Name != name })
+		}
+
+		By("recording the pending create before the response gets lost")
+		key := newService("crash-lost-api")
+		creates := jsmServer.Calls("createDevOpsService")
+		jsmServer.LoseNextResponse("createDevOpsService")
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).To(HaveOccurred())
+		service := &jsmv1beta1.JSMService{}
+		Expect(k8sClient.Get(ctx, key, service)).To(Succeed())
+		Expect(service.Status.ID).To(BeEmpty())
+		Expect(service.Status.PendingCreate).NotTo(BeNil())
+		Expect(service.Status.PendingCreate.Name).To(Equal("crash-lost-api"))
+
+		_, service = reconcileService(key)
+		Expect(jsmServer.Calls("createDevOpsService")).To(Equal(creates + 1))
+		created := servicesNamed("crash-lost-api")
+		Expect(created).To(HaveLen(1))
+		Expect(service.Status.ID).To(Equal(created[0].ID))
+		Expect(service.Status.PendingCreate).To(BeNil())
+		Expect(service.Status.Adoption).To(BeNil())
+		expectReady(service.Status.Conditions, service.Generation)
+
+		By("finding the service when the status write after the create fails")
+		key = newService("crash-status-api")
+		creates = jsmServer.Calls("createDevOpsService")
+		crashing := &JSMServiceReconciler{
+			Client:    &failingStatusClient{Client: k8sClient, failAt: 2},
+			Scheme:    k8sClient.Scheme(),
+			JSMClient: jsmClient,
+			Recorder:  recorder,
+		}
+		_, err = crashing.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).To(HaveOccurred())
+		Expect(servicesNamed("crash-status-api")).To(HaveLen(1))
+
+		_, service = reconcileService(key)
+		Expect(jsmServer.Calls("createDevOpsService")).To(Equal(creates + 1))
+		created = servicesNamed("crash-status-api")
+		Expect(created).To(HaveLen(1))
+		Expect(service.Status.ID).To(Equal(created[0].ID))
+		Expect(service.Status.PendingCreate).To(BeNil())
+
+		By("creating again if the interrupted create left nothing behind")
+		key = newService("crash-failed-api")
+		jsmServer.FailNext("createDevOpsService", http.StatusBadGateway, "bad gateway")
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).To(HaveOccurred())
+		_, service = reconcileService(key)
+		Expect(servicesNamed("crash-failed-api")).To(HaveLen(1))
+		Expect(service.Status.PendingCreate).To(BeNil())
+		expectReady(service.Status.Conditions, service.Generation)
+
+		By("refusing a service of the recorded name that belongs to another team")
+		key = newService("crash-foreign-api")
+		foreignID := jsmServer.AddTeam("crash-foreign")
+		foreign := jsmServer.AddService(fake.Service{Name: "crash-foreign-api", TierLevel: 2, ResponderTeams: []string{foreignID}})
+		Expect(k8sClient.Get(ctx, key, service)).To(Succeed())
+		service.Status.PendingCreate = &jsmv1beta1.PendingCreate{Name: "crash-foreign-api", Time: metav1.Now()}
+		Expect(k8sClient.Status().Update(ctx, service)).To(Succeed())
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).To(MatchError(reconcile.TerminalError(nil)))
+		Expect(err).To(MatchError(ContainSubstring(foreignID)))
+		Expect(k8sClient.Get(ctx, key, service)).To(Succeed())
+		Expect(service.Status.ID).To(BeEmpty())
+		Expect(meta.FindStatusCondition(service.Status.Conditions, jsmv1beta1.ConditionRemoteSynced).Reason).
+			To(Equal(jsmv1beta1.ReasonAdoptionRefused))
+		remote, _ := jsmServer.Service(foreign.ID)
+		Expect(remote.ResponderTeams).To(ConsistOf(foreignID))
+	})
+
+	It("renames the service in place", func() {
+		createTeam("rename-sre")
+		key := types.NamespacedName{Name: "rename-api", Namespace: namespace}
+		service := &jsmv1beta1.JSMService{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: namespace},
+			Spec: jsmv1beta1.JSMServiceSpec{
+				Name:      "Checkout",
+				TierLevel: 2,
+				TeamRef:   &jsmv1beta1.JSMTeamRef{Name: "rename-sre"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, service)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, service)
+		_, service = reconcileService(key)
+		id := service.Status.ID
+		Expect(service.Status.Name).To(Equal("Checkout"))
+
+		By("keeping the ID when spec.name changes")
+		creates := jsmServer.Calls("createDevOpsService")
+		updateSpec(key, func(spec *jsmv1beta1.JSMServiceSpec) { spec.Name = "Checkout API" })
+		_, service = reconcileService(key)
+		Expect(service.Status.ID).To(Equal(id))
+		Expect(service.Status.Name).To(Equal("Checkout API"))
+		remote, _ := jsmServer.Service(id)
+		Expect(remote.Name).To(Equal("Checkout API"))
+		Expect(jsmServer.Calls("createDevOpsService")).To(Equal(creates))
+		Expect(recordedEvents()).To(ContainElement(HavePrefix(fmt.Sprintf(`Normal Renamed Renamed JSM service %s from "Checkout" to "Checkout API"`, id))))
+
+		By("refreshing a conflicting revision by ID while renaming")
+		jsmServer.EditService(id, func(s *fake.Service) { s.Description = "edited in UI" })
+		updateSpec(key, func(spec *jsmv1beta1.JSMServiceSpec) { spec.Name = "Payments Checkout" })
+		result, _ := reconcileService(key)
+		Expect(result.Requeue).To(BeTrue())
+		_, service = reconcileService(key)
+		remote, _ = jsmServer.Service(id)
+		Expect(remote.Name).To(Equal("Payments Checkout"))
+		expectReady(service.Status.Conditions, service.Generation)
+
+		By("stalling on a name another service already has")
+		jsmServer.AddService(fake.Service{Name: "Billing", TierLevel: 2})
+		updateSpec(key, func(spec *jsmv1beta1.JSMServiceSpec) { spec.Name = "Billing" })
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).To(MatchError(reconcile.TerminalError(nil)))
+		Expect(k8sClient.Get(ctx, key, service)).To(Succeed())
+		Expect(service.Status.ID).To(Equal(id))
+		Expect(service.Status.Name).To(Equal("Payments Checkout"))
+		condition := meta.FindStatusCondition(service.Status.Conditions, jsmv1beta1.ConditionRemoteSynced)
+		Expect(condition.Reason).To(Equal(jsmv1beta1.ReasonNameConflict))
+		Expect(meta.IsStatusConditionTrue(service.Status.Conditions, jsmv1beta1.ConditionStalled)).To(BeTrue())
+	})
+
+	It("resolves the tier by name and reports unknown tiers", func() {
+		createTeam("tier-sre")
+		key := types.NamespacedName{Name: "tier-api", Namespace: namespace}
+		service := &jsmv1beta1.JSMService{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: namespace},
+			Spec: jsmv1beta1.JSMServiceSpec{
+				TierName: "Critical",
+				TeamRef:  &jsmv1beta1.JSMTeamRef{Name: "tier-sre"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, service)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, service)
+
+		By("creating the service with the named tier")
+		_, service = reconcileService(key)
+		Expect(service.Status.TierLevel).To(Equal(1))
+		Expect(service.Status.TierName).To(Equal("Critical"))
+		remote, _ := jsmServer.Service(service.Status.ID)
+		Expect(remote.TierLevel).To(Equal(1))
+		Expect(meta.IsStatusConditionTrue(service.Status.Conditions, jsmv1beta1.ConditionTierResolved)).To(BeTrue())
+
+		By("refusing a tier that doesn't exist")
+		updateSpec(key, func(spec *jsmv1beta1.JSMServiceSpec) {
+			spec.TierName = "Sev0"
+		})
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).To(MatchError(reconcile.TerminalError(nil)))
+		Expect(k8sClient.Get(ctx, key, service)).To(Succeed())
+		condition := meta.FindStatusCondition(service.Status.Conditions, jsmv1beta1.ConditionTierResolved)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(jsmv1beta1.ReasonUnknownTier))
+		Expect(meta.IsStatusConditionTrue(service.Status.Conditions, jsmv1beta1.ConditionStalled)).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(service.Status.Conditions, jsmv1beta1.ConditionReady)).To(BeTrue())
+		Expect(service.Status.ObservedGeneration).To(Equal(service.Generation))
+		remote, _ = jsmServer.Service(service.Status.ID)
+		Expect(remote.TierLevel).To(Equal(1))
+	})
+
+	It("reports a referenced team without an ID as still reconciling", func() {
+		team := &jsmv1beta1.JSMTeam{
+			ObjectMeta: metav1.ObjectMeta{Name: "pending-sre", Namespace: namespace},
+			Spec:       jsmv1beta1.JSMTeamSpec{Name: "pending-sre"},
+		}
+		Expect(k8sClient.Create(ctx, team)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, team)
+
+		key := types.NamespacedName{Name: "pending-api", Namespace: namespace}
+		service := &jsmv1beta1.JSMService{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: namespace},
+			Spec: jsmv1beta1.JSMServiceSpec{
+				TierLevel: 2,
+				TeamRef:   &jsmv1beta1.JSMTeamRef{Name: "pending-sre"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, service)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, service)
+
+		_, service = reconcileService(key)
+		Expect(service.Status.ID).To(BeEmpty())
+		Expect(service.Status.ObservedGeneration).To(Equal(service.Generation))
+		condition := meta.FindStatusCondition(service.Status.Conditions, jsmv1beta1.ConditionTeamResolved)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(jsmv1beta1.ReasonTeamNotReady))
+		Expect(meta.IsStatusConditionTrue(service.Status.Conditions, jsmv1beta1.ConditionReconciling)).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(service.Status.Conditions, jsmv1beta1.ConditionStalled)).To(BeTrue())
+		Expect(meta.FindStatusCondition(service.Status.Conditions, jsmv1beta1.ConditionReady).Reason).
+			To(Equal(jsmv1beta1.ReasonTeamNotReady))
+	})
+
+	It("waits for a referenced team that doesn't exist yet", func() {
+		key := types.NamespacedName{Name: "waiting-api", Namespace: namespace}
+		service := &jsmv1beta1.JSMService{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: namespace},
+			Spec: jsmv1beta1.JSMServiceSpec{
+				TierLevel: 2,
+				TeamRef:   &jsmv1beta1.JSMTeamRef{Name: "missing-sre"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, service)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, service)
+
+		result, service := reconcileService(key)
+		Expect(result).To(Equal(reconcile.Result{}))
+		Expect(service.Status.ID).To(BeEmpty())
+		condition := meta.FindStatusCondition(service.Status.Conditions, jsmv1beta1.ConditionTeamResolved)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(jsmv1beta1.ReasonTeamNotFound))
+		Expect(meta.IsStatusConditionTrue(service.Status.Conditions, jsmv1beta1.ConditionReconciling)).To(BeTrue())
+	})
+
+	It("enqueues the services referencing a team when it changes", func() {
+		services := []client.Object{
+			&jsmv1beta1.JSMService{
+				ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: namespace},
+				Spec:       jsmv1beta1.JSMServiceSpec{TeamRef: &jsmv1beta1.JSMTeamRef{Name: "sre"}},
+			},
+			&jsmv1beta1.JSMService{
+				ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: namespace},
+				Spec:       jsmv1beta1.JSMServiceSpec{TeamRef: &jsmv1beta1.JSMTeamRef{Name: "sre"}},
+			},
+			&jsmv1beta1.JSMService{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: namespace},
+				Spec:       jsmv1beta1.JSMServiceSpec{TeamRef: &jsmv1beta1.JSMTeamRef{Name: "frontend"}},
+			},
+			&jsmv1beta1.JSMService{
+				ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "other"},
+				Spec:       jsmv1beta1.JSMServiceSpec{TeamRef: &jsmv1beta1.JSMTeamRef{Name: "sre"}},
+			},
+			&jsmv1beta1.JSMService{
+				ObjectMeta: metav1.ObjectMeta{Name: "billing", Namespace: "other"},
+				Spec: jsmv1beta1.JSMServiceSpec{TeamRefs: []jsmv1beta1.JSMTeamRef{
+					{Name: "billing"},
+					{Name: "sre", Namespace: namespace, Role: jsmv1beta1.TeamRoleResponder},
+				}},
+			},
+		}
+		indexed := fakeclient.NewClientBuilder().
+			WithScheme(k8sClient.Scheme()).
+			WithObjects(services...).
+			WithIndex(&jsmv1beta1.JSMService{}, teamRefIndex, indexTeamRefs).
+			Build()
+		watcher := &JSMServiceReconciler{Client: indexed, Scheme: indexed.Scheme()}
+
+		team := &jsmv1beta1.JSMTeam{ObjectMeta: metav1.ObjectMeta{Name: "sre", Namespace: namespace}}
+		Expect(watcher.servicesForTeam(ctx, team)).To(ConsistOf(
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: "api", Namespace: namespace}},
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: "worker", Namespace: namespace}},
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: "billing", Namespace: "other"}},
+		))
+
+		resolved := team.DeepCopy()
+		resolved.Status.ID = "ari:cloud:opsgenie::team/sre"
+		Expect(teamChanged.Update(event.UpdateEvent{ObjectOld: team, ObjectNew: resolved})).To(BeTrue())
+		reported := resolved.DeepCopy()
+		reported.Status.ObservedGeneration++
+		Expect(teamChanged.Update(event.UpdateEvent{ObjectOld: resolved, ObjectNew: reported})).To(BeFalse())
+		allowed := resolved.DeepCopy()
+		allowed.Spec.AllowedNamespaces = []string{"other"}
+		allowed.Generation++
+		Expect(teamChanged.Update(event.UpdateEvent{ObjectOld: resolved, ObjectNew: allowed})).To(BeTrue())
+		Expect(teamChanged.Create(event.CreateEvent{Object: team})).To(BeTrue())
+	})
+
+	It("corrects or reports drift on the periodic resync", func() {
+		createTeam("drift-sre")
+		newService := func(name string, policy jsmv1beta1.DriftPolicy) types.NamespacedName {
+			key := types.NamespacedName{Name: name, Namespace: namespace}
+			service := &jsmv1beta1.JSMService{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+				Spec: jsmv1beta1.JSMServiceSpec{
+					Description:    "from spec",
+					TierLevel:      2,
+					ServiceTypeKey: "APPLICATIONS",
+					TeamRef:        &jsmv1beta1.JSMTeamRef{Name: "drift-sre"},
+					DriftPolicy:    policy,
+				},
+			}
+			Expect(k8sClient.Create(ctx, service)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, service)
+			return key
+		}
+
+		By("not reading the remote service before the interval passed")
+		reconciler.ResyncInterval = time.Hour
+		key := newService("drift-api", "")
+		result, service := reconcileService(key)
+		Expect(result.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+		reads := jsmServer.Calls("devOpsService")
+		otherTier, err := jsmClient.GetTierIDByLevel(ctx, 4)
+		Expect(err).NotTo(HaveOccurred())
+		jsmServer.EditService(service.Status.ID, func(s *fake.Service) {
+			s.Description = "edited in UI"
+			s.TierID, s.TierLevel = otherTier, 4
+		})
+		_, _ = reconcileService(key)
+		Expect(jsmServer.Calls("devOpsService")).To(Equal(reads))
+
+		By("correcting the drifted fields once the annotation makes it due")
+		Expect(k8sClient.Get(ctx, key, service)).To(Succeed())
+		service.Annotations = map[string]string{jsmv1beta1.ResyncIntervalAnnotation: "1ns"}
+		Expect(k8sClient.Update(ctx, service)).To(Succeed())
+		_, service = reconcileService(key)
+		Expect(service.Status.DriftedFields).To(ConsistOf("description", "tier"))
+		remote, _ := jsmServer.Service(service.Status.ID)
+		Expect(remote.Description).To(Equal("from spec"))
+		Expect(remote.TierLevel).To(Equal(2))
+		drifted := meta.FindStatusCondition(service.Status.Conditions, jsmv1beta1.ConditionDrifted)
+		Expect(drifted).NotTo(BeNil())
+		Expect(drifted.Status).To(Equal(metav1.ConditionFalse))
+		Expect(drifted.Reason).To(Equal(jsmv1beta1.ReasonDriftCorrected))
+		expectReady(service.Status.Conditions, service.Generation)
+
+		By("recreating a service deleted in JSM")
+		deletedID := service.Status.ID
+		Expect(jsmClient.DeleteService(ctx, deletedID)).To(Succeed())
+		_, service = reconcileService(key)
+		Expect(service.Status.ID).NotTo(BeEmpty())
+		Expect(service.Status.ID).NotTo(Equal(deletedID))
+		Expect(service.Status.DriftedFields).To(ConsistOf("service"))
+		_, ok := jsmServer.Service(service.Status.ID)
+		Expect(ok).To(BeTrue())
+
+		By("only reporting drift with the Report policy")
+		reconciler.ResyncInterval = time.Nanosecond
+		key = newService("drift-report-api", jsmv1beta1.DriftPolicyReport)
+		_, service = reconcileService(key)
+		jsmServer.EditService(service.Status.ID, func(s *fake.Service) { s.Description = "edited in UI" })
+		_, service = reconcileService(key)
+		Expect(service.Status.DriftedFields).To(ConsistOf("description"))
+		Expect(meta.IsStatusConditionTrue(service.Status.Conditions, jsmv1beta1.ConditionDrifted)).To(BeTrue())
+		remote, _ = jsmServer.Service(service.Status.ID)
+		Expect(remote.Description).To(Equal("edited in UI"))
+		Expect(service.Status.Revision).To(Equal(remote.Revision))
+
+		By("clearing the report once the remote service matches again")
+		jsmServer.EditService(service.Status.ID, func(s *fake.Service) { s.Description = "from spec" })
+		_, service = reconcileService(key)
+		Expect(service.Status.DriftedFields).To(BeEmpty())
+		Expect(meta.IsStatusConditionFalse(service.Status.Conditions, jsmv1beta1.ConditionDrifted)).To(BeTrue())
+
+		By("reporting a service type that can't be corrected in place")
+		key = newService("drift-type-api", "")
+		_, service = reconcileService(key)
+		jsmServer.EditService(service.Status.ID, func(s *fake.Service) {
+			s.Description = "edited in UI"
+			s.ServiceType = "BUSINESS_SERVICES"
+		})
+		_, service = reconcileService(key)
+		Expect(service.Status.DriftedFields).To(ConsistOf("description", "serviceType"))
+		remote, _ = jsmServer.Service(service.Status.ID)
+		Expect(remote.Description).To(Equal("from spec"))
+		drifted = meta.FindStatusCondition(service.Status.Conditions, jsmv1beta1.ConditionDrifted)
+		Expect(drifted).NotTo(BeNil())
+		Expect(drifted.Status).To(Equal(metav1.ConditionTrue))
+		Expect(drifted.Message).To(ContainSubstring("service type"))
+	})
+
+	It("reports a service deleted in JSM under the Report policy as not ready", func() {
+		createTeam("deleted-report-sre")
+		reconciler.ResyncInterval = time.Nanosecond
+		key := types.NamespacedName{Name: "deleted-report-api", Namespace: namespace}
+		service := &jsmv1beta1.JSMService{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: namespace},
+			Spec: jsmv1beta1.JSMServiceSpec{
+				TierLevel:   2,
+				TeamRef:     &jsmv1beta1.JSMTeamRef{Name: "deleted-report-sre"},
+				DriftPolicy: jsmv1beta1.DriftPolicyReport,
+			},
+		}
+		Expect(k8sClient.Create(ctx, service)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, service)
+		_, service = reconcileService(key)
+		expectReady(service.Status.Conditions, service.Generation)
+
+		deletedID := service.Status.ID
+		Expect(jsmClient.DeleteService(ctx, deletedID)).To(Succeed())
+		creates := jsmServer.Calls("createDevOpsService")
+		for range 2 {
+			_, service = reconcileService(key)
+			Expect(service.Status.ID).To(Equal(deletedID))
+			ready := meta.FindStatusCondition(service.Status.Conditions, jsmv1beta1.ConditionReady)
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(ready.Reason).To(Equal(jsmv1beta1.ReasonRemoteDeleted))
+			Expect(meta.IsStatusConditionTrue(service.Status.Conditions, jsmv1beta1.ConditionDrifted)).To(BeTrue())
+		}
+		Expect(jsmServer.Calls("createDevOpsService")).To(Equal(creates))
+	})
+
+	It("records an event for every JSM mutation and failure", func() {
+		sreID := createTeam("events-sre")
+		opsID := createTeam("events-ops")
+		key := types.NamespacedName{Name: "events-api", Namespace: namespace}
+		service := &jsmv1beta1.JSMService{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: namespace},
+			Spec: jsmv1beta1.JSMServiceSpec{
+				TierLevel:      2,
+				TeamRef:        &jsmv1beta1.JSMTeamRef{Name: "events-sre"},
+				DeletionPolicy: jsmv1beta1.DeletionPolicyDelete,
+			},
+		}
+		Expect(k8sClient.Create(ctx, service)).To(Succeed())
+		annotated := func(op, id string) string {
+			return fmt.Sprintf("map[%s:%s %s:%s]", jsmv1beta1.EventAnnotationARI, id, jsmv1beta1.EventAnnotationOperation, op)
+		}
+
+		By("warning about a failed create")
+		recordedEvents()
+		jsmServer.FailNext("createDevOpsService", http.StatusBadRequest, "invalid")
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).To(HaveOccurred())
+		Expect(recordedEvents()).To(ConsistOf(
+			And(HavePrefix("Warning CreateFailed CreateService failed"), HaveSuffix(fmt.Sprintf("map[%s:CreateService]", jsmv1beta1.EventAnnotationOperation))),
+		))
+
+		By("recording the create and the link")
+		_, service = reconcileService(key)
+		id := service.Status.ID
+		Expect(recordedEvents()).To(ConsistOf(
+			And(HavePrefix("Normal Created Created JSM service "+id), HaveSuffix(annotated("CreateService", id))),
+			And(HavePrefix("Normal TeamLinked Linked JSM service "+id+" with Opsgenie team "+sreID), HaveSuffix(annotated("CreateOpsgenieTeamRelationship", id))),
+		))
+
+		By("recording the update, the retried conflict and the relinking")
+		jsmServer.EditService(id, func(s *fake.Service) { s.Description = "edited in UI" })
+		updateSpec(key, func(spec *jsmv1beta1.JSMServiceSpec) {
+			spec.Description = "from spec"
+			spec.TeamRef = &jsmv1beta1.JSMTeamRef{Name: "events-ops"}
+		})
+		_, _ = reconcileService(key)
+		_, _ = reconcileService(key)
+		Expect(recordedEvents()).To(ConsistOf(
+			And(HavePrefix("Normal ConflictRetried"), HaveSuffix(annotated("UpdateService", id))),
+			And(HavePrefix("Normal Updated Updated JSM service "+id), HaveSuffix(annotated("UpdateService", id))),
+			HavePrefix("Normal TeamLinked Linked JSM service "+id+" with Opsgenie team "+opsID),
+			And(HavePrefix("Normal TeamUnlinked Unlinked JSM service "+id+" from Opsgenie team "+sreID), HaveSuffix(annotated("DeleteOpsgenieTeamRelationship", id))),
+		))
+
+		By("recording the deletion")
+		Expect(k8sClient.Delete(ctx, service)).To(Succeed())
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recordedEvents()).To(ConsistOf(
+			And(HavePrefix("Normal Deleted Deleted JSM service "+id), HaveSuffix(annotated("DeleteService", id))),
+		))
+	})
+
+	It("plans the changes of a dry run without making them", func() {
+		sreID := createTeam("plan-sre")
+		key := types.NamespacedName{Name: "plan-api", Namespace: namespace}
+		service := &jsmv1beta1.JSMService{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: namespace},
+			Spec: jsmv1beta1.JSMServiceSpec{
+				Description:    "from spec",
+				TierLevel:      2,
+				TeamRef:        &jsmv1beta1.JSMTeamRef{Name: "plan-sre"},
+				DeletionPolicy: jsmv1beta1.DeletionPolicyDelete,
+				DryRun:         true,
+			},
+		}
+		Expect(k8sClient.Create(ctx, service)).To(Succeed())
+		mutations := func() int {
+			return jsmServer.Calls("createDevOpsService") + jsmServer.Calls("updateDevOpsService") +
+				jsmServer.Calls("deleteDevOpsService") + jsmServer.Calls("createDevOpsServiceAndOpsgenieTeamRelationship") +
+				jsmServer.Calls("deleteDevOpsServiceAndOpsgenieTeamRelationship")
+		}
+
+		By("planning the create of a new service")
+		recordedEvents()
+		before := mutations()
+		_, service = reconcileService(key)
+		Expect(mutations()).To(Equal(before))
+		Expect(service.Status.ID).To(BeEmpty())
+		Expect(service.Status.Plan).To(Equal([]string{
+			fmt.Sprintf(`Create JSM service "plan-api" with tier "High" and responders %s`, sreID),
+			"Link the new JSM service with Opsgenie team " + sreID,
+		}))
+		synced := meta.FindStatusCondition(service.Status.Conditions, jsmv1beta1.ConditionRemoteSynced)
+		Expect(synced).NotTo(BeNil())
+		Expect(synced.Status).To(Equal(metav1.ConditionFalse))
+		Expect(synced.Reason).To(Equal(jsmv1beta1.ReasonDryRun))
+		Expect(recordedEvents()).To(ConsistOf(And(
+			HavePrefix("Normal Planned Dry run, planned: Create JSM service"),
+			HaveSuffix(fmt.Sprintf("map[%s:CreateService]", jsmv1beta1.EventAnnotationOperation)),
+		)))
+
+		By("applying the spec once the dry run ends")
+		updateSpec(key, func(spec *jsmv1beta1.JSMServiceSpec) { spec.DryRun = false })
+		_, service = reconcileService(key)
+		id := service.Status.ID
+		Expect(id).NotTo(BeEmpty())
+		Expect(service.Status.Plan).To(BeEmpty())
+		expectReady(service.Status.Conditions, service.Generation)
+
+		By("planning an update with the operator-wide dry run")
+		reconciler.DryRun = true
+		jsmServer.EditService(id, func(s *fake.Service) { s.Description = "edited in UI" })
+		updateSpec(key, func(spec *jsmv1beta1.JSMServiceSpec) { spec.TeamRef = nil })
+		before = mutations()
+		_, service = reconcileService(key)
+		Expect(mutations()).To(Equal(before))
+		Expect(service.Status.Plan).To(Equal([]string{
+			fmt.Sprintf("Update JSM service %s: description, responders", id),
+			fmt.Sprintf("Unlink JSM service %s from Opsgenie team %s", id, sreID),
+		}))
+		remote, _ := jsmServer.Service(id)
+		Expect(remote.Description).To(Equal("edited in UI"))
+		Expect(recordedEvents()).To(ContainElement(And(
+			HavePrefix("Normal Planned Dry run, planned: Update JSM service"),
+			HaveSuffix(fmt.Sprintf("map[%s:%s %s:UpdateService]", jsmv1beta1.EventAnnotationARI, id, jsmv1beta1.EventAnnotationOperation)),
+		)))
+
+		By("leaving the service behind on deletion")
+		Expect(k8sClient.Delete(ctx, service)).To(Succeed())
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errors.IsNotFound(k8sClient.Get(ctx, key, &jsmv1beta1.JSMService{}))).To(BeTrue())
+		_, ok := jsmServer.Service(id)
+		Expect(ok).To(BeTrue())
+		Expect(recordedEvents()).To(ContainElement(HavePrefix("Normal Planned Dry run, planned: Delete JSM service " + id)))
+	})
+
+	It("honours the suspend, reconcile-at and read-only annotations", func() {
+		createTeam("control-sre")
+		key := types.NamespacedName{Name: "control-api", Namespace: namespace}
+		service := &jsmv1beta1.JSMService{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        key.Name,
+				Namespace:   namespace,
+				Annotations: map[string]string{jsmv1beta1.SuspendAnnotation: "true"},
+			},
+			Spec: jsmv1beta1.JSMServiceSpec{
+				Description:    "from spec",
+				TierLevel:      2,
+				TeamRef:        &jsmv1beta1.JSMTeamRef{Name: "control-sre"},
+				DeletionPolicy: jsmv1beta1.DeletionPolicyDelete,
+			},
+		}
+		Expect(k8sClient.Create(ctx, service)).To(Succeed())
+		setAnnotation := func(annotation, value string) {
+			service := &jsmv1beta1.JSMService{}
+			Expect(k8sClient.Get(ctx, key, service)).To(Succeed())
+			if value == "" {
+				delete(service.Annotations, annotation)
+			} else {
+				metav1.SetMetaDataAnnotation(&service.ObjectMeta, annotation, value)
+			}
+			Expect(k8sClient.Update(ctx, service)).To(Succeed())
+		}
+
+		By("not touching JSM while suspended")
+		creates := jsmServer.Calls("createDevOpsService")
+		_, service = reconcileService(key)
+		Expect(jsmServer.Calls("createDevOpsService")).To(Equal(creates))
+		Expect(service.Status.ID).To(BeEmpty())
+		suspended := meta.FindStatusCondition(service.Status.Conditions, jsmv1beta1.ConditionSuspended)
+		Expect(suspended).NotTo(BeNil())
+		Expect(suspended.Status).To(Equal(metav1.ConditionTrue))
+		Expect(suspended.Reason).To(Equal(jsmv1beta1.ReasonSuspended))
+
+		By("creating the service once resumed")
+		setAnnotation(jsmv1beta1.SuspendAnnotation, "")
+		_, service = reconcileService(key)
+		Expect(service.Status.ID).NotTo(BeEmpty())
+		Expect(meta.FindStatusCondition(service.Status.Conditions, jsmv1beta1.ConditionSuspended)).To(BeNil())
+		expectReady(service.Status.Conditions, service.Generation)
+
+		By("correcting drift right away when a resync is requested")
+		jsmServer.EditService(service.Status.ID, func(s *fake.Service) { s.Description = "edited in UI" })
+		_, _ = reconcileService(key)
+		remote, _ := jsmServer.Service(service.Status.ID)
+		Expect(remote.Description).To(Equal("edited in UI"))
+		setAnnotation(jsmv1beta1.ReconcileAtAnnotation, "2025-06-01T10:00:00Z")
+		_, service = reconcileService(key)
+		Expect(service.Status.DriftedFields).To(ConsistOf("description"))
+		Expect(service.Status.LastHandledReconcileAt).To(Equal("2025-06-01T10:00:00Z"))
+		remote, _ = jsmServer.Service(service.Status.ID)
+		Expect(remote.Description).To(Equal("from spec"))
+
+		By("not resyncing again for a handled request")
+		reads := jsmServer.Calls("devOpsService")
+		_, _ = reconcileService(key)
+		Expect(jsmServer.Calls("devOpsService")).To(Equal(reads))
+
+		By("reporting instead of applying spec changes while read-only")
+		setAnnotation(jsmv1beta1.ReadOnlyAnnotation, "true")
+		updateSpec(key, func(spec *jsmv1beta1.JSMServiceSpec) { spec.Description = "changed spec" })
+		updates := jsmServer.Calls("updateDevOpsService")
+		_, service = reconcileService(key)
+		Expect(jsmServer.Calls("updateDevOpsService")).To(Equal(updates))
+		Expect(service.Status.DriftedFields).To(ConsistOf("description"))
+		drifted := meta.FindStatusCondition(service.Status.Conditions, jsmv1beta1.ConditionDrifted)
+		Expect(drifted).NotTo(BeNil())
+		Expect(drifted.Status).To(Equal(metav1.ConditionTrue))
+		Expect(drifted.Message).To(ContainSubstring("read-only"))
+		remote, _ = jsmServer.Service(service.Status.ID)
+		Expect(remote.Description).To(Equal("from spec"))
+
+		By("leaving the service behind when a read-only resource is deleted")
+		Expect(k8sClient.Delete(ctx, service)).To(Succeed())
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errors.IsNotFound(k8sClient.Get(ctx, key, &jsmv1beta1.JSMService{}))).To(BeTrue())
+		_, ok := jsmServer.Service(service.Status.ID)
+		Expect(ok).To(BeTrue())
+	})
+
+	It("refuses to create a read-only service", func() {
+		key := types.NamespacedName{Name: "observed-api", Namespace: namespace}
+		service := &jsmv1beta1.JSMService{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        key.Name,
+				Namespace:   namespace,
+				Annotations: map[string]string{jsmv1beta1.ReadOnlyAnnotation: "true"},
+			},
+			Spec: jsmv1beta1.JSMServiceSpec{TierLevel: 3},
+		}
+		Expect(k8sClient.Create(ctx, service)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, service)
+
+		creates := jsmServer.Calls("createDevOpsService")
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).To(HaveOccurred())
+		Expect(jsmServer.Calls("createDevOpsService")).To(Equal(creates))
+		Expect(k8sClient.Get(ctx, key, service)).To(Succeed())
+		synced := meta.FindStatusCondition(service.Status.Conditions, jsmv1beta1.ConditionRemoteSynced)
+		Expect(synced).NotTo(BeNil())
+		Expect(synced.Reason).To(Equal(jsmv1beta1.ReasonReadOnly))
+		Expect(meta.IsStatusConditionTrue(service.Status.Conditions, jsmv1beta1.ConditionStalled)).To(BeTrue())
+	})
+
+	It("deletes or orphans the remote service according to the deletion policy", func() {
+		createTeam("delete-sre")
+		newService := func(name string, policy jsmv1beta1.DeletionPolicy) (types.NamespacedName, *jsmv1beta1.JSMService) {
+			key := types.NamespacedName{Name: name, Namespace: namespace}
+			service := &jsmv1beta1.JSMService{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+				Spec: jsmv1beta1.JSMServiceSpec{
+					TierLevel:      4,
+					TeamRef:        &jsmv1beta1.JSMTeamRef{Name: "delete-sre"},
+					DeletionPolicy: policy,
+				},
+			}
+			Expect(k8sClient.Create(ctx, service)).To(Succeed())
+			_, service = reconcileService(key)
+			Expect(service.Finalizers).To(ContainElement(jsmv1beta1.ServiceFinalizer))
+			return key, service
+		}
+		isGone := func(key types.NamespacedName) bool {
+			return errors.IsNotFound(k8sClient.Get(ctx, key, &jsmv1beta1.JSMService{}))
+		}
+
+		By("deleting the remote service with the Delete policy")
+		key, service := newService("delete-api", jsmv1beta1.DeletionPolicyDelete)
+		jsmServer.FailNext("deleteDevOpsService", http.StatusInternalServerError, "boom")
+		Expect(k8sClient.Delete(ctx, service)).To(Succeed())
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).To(HaveOccurred())
+		Expect(k8sClient.Get(ctx, key, service)).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(service.Status.Conditions, jsmv1beta1.ConditionDeletionFailed)).To(BeTrue())
+
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(isGone(key)).To(BeTrue())
+		_, ok := jsmServer.Service(service.Status.ID)
+		Expect(ok).To(BeFalse())
+
+		By("leaving the remote service behind with the default policy")
+		key, service = newService("orphan-api", "")
+		Expect(k8sClient.Delete(ctx, service)).To(Succeed())
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(isGone(key)).To(BeTrue())
+		_, ok = jsmServer.Service(service.Status.ID)
+		Expect(ok).To(BeTrue())
+	})
+})
@@ -18,16 +18,19 @@ package controller
 
 import (
 	"context"
-	"time"
+	"errors"
+	"fmt"
 
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/client-go/util/workqueue"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
-	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	jsmv1beta1 "github.com/artemlive/jsm-operator/api/v1beta1"
 	jsmclient "github.com/artemlive/jsm-operator/internal/client"
@@ -37,23 +40,25 @@ import (
 type JSMTeamReconciler struct {
 	client.Client
 	Scheme    *runtime.Scheme
-	JSMClient *jsmclient.JSMClient
+	JSMClient jsmclient.API
+	// Recorder receives the events about JSM requests, nil drops them.
+	Recorder record.EventRecorder
+	// DryRun plans the changes of every managed team instead of making them.
+	DryRun bool
+	// Queue tunes the workers and backoff of the controller.
+	Queue QueueConfig
 }
 
 // +kubebuilder:rbac:groups=jsm.macpaw.dev,resources=jsmteams,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=jsm.macpaw.dev,resources=jsmteams/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=jsm.macpaw.dev,resources=jsmteams/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
-// Reconcile is part of the main kubernetes reconciliation loop which aims to
-// move the current state of the cluster closer to the desired state.
-// TODO(user): Modify the Reconcile function to compare the state specified by
-// the JSMTeam object against the actual cluster state, and then
-// perform operations to make the cluster state reflect the state specified by
-// the user.
-//
-
-// For more details, check Reconcile and its Result here:
-// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.20.2/pkg/reconcile
+// Reconcile resolves the Opsgenie team of a JSMTeam, or manages it when the
+// team is managed. A team being deleted is handed to handleTeamDeletion.
+// Otherwise the finalizer is added to a managed team and removed from any
+// other, and unless the team is suspended reconcileTeam resolves, creates or
+// updates the Opsgenie team. The conditions and the status are written last.
 func (r *JSMTeamReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 
@@ -62,51 +67,125 @@ func (r *JSMTeamReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	teamName := team.Name
-	if team.Spec.Name != "" {
-		teamName = team.Spec.Name
+	if !team.DeletionTimestamp.IsZero() {
+		return r.handleTeamDeletion(ctx, &team)
+	}
+
+	// only a managed team has anything to clean up
+	var finalizerChanged bool
+	if isManaged(&team) {
+		finalizerChanged = controllerutil.AddFinalizer(&team, jsmv1beta1.TeamFinalizer)
+	} else {
+		finalizerChanged = controllerutil.RemoveFinalizer(&team, jsmv1beta1.TeamFinalizer)
+	}
+	if finalizerChanged {
+		if err := r.Update(ctx, &team); err != nil {
+			logger.Error(err, "unable to update JSMTeam finalizers")
+			return ctrl.Result{}, err
+		}
+	}
+
+	original := team.Status.DeepCopy()
+	if isSuspended(&team) {
+		logger.Info("JSMTeam is suspended, skipping", "annotation", jsmv1beta1.SuspendAnnotation)
+		setSuspended(&team.Status.Conditions, team.Generation)
+		if !equality.Semantic.DeepEqual(original, &team.Status) {
+			if err := r.Status().Update(ctx, &team); err != nil {
+				logger.Error(err, "unable to update JSMTeam status")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+	clearSuspended(&team.Status.Conditions)
+
+	reconcileAt, forced := reconcileRequest(&team, team.Status.LastHandledReconcileAt)
+	result, err := r.reconcileTeam(ctx, &team, forced)
+	if forced && err == nil {
+		team.Status.LastHandledReconcileAt = reconcileAt
+	}
+	setReadiness(&team.Status.Conditions, team.Generation, teamConditions(&team), result, err)
+	team.Status.ObservedGeneration = team.Generation
+
+	if !equality.Semantic.DeepEqual(original, &team.Status) {
+		if err := r.Status().Update(ctx, &team); err != nil {
+			logger.Error(err, "unable to update JSMTeam status")
+			return ctrl.Result{}, err
+		}
+	}
+	return result, err
+}
+
+// teamConditions are the domain conditions of a JSMTeam. Ready summarizes
+// them.
+func teamConditions(team *jsmv1beta1.JSMTeam) []string {
+	if isManaged(team) {
+		return []string{jsmv1beta1.ConditionTeamResolved, jsmv1beta1.ConditionRemoteSynced}
 	}
+	return []string{jsmv1beta1.ConditionTeamResolved}
+}
+
+// reconcileTeam resolves the ARI of the team into its status and reports it
+// on the TeamResolved condition. A forced reconcile looks the team up again
+// instead of trusting the ARI in the status. Resolving only reads from JSM,
+// so a read-only team is reconciled as usual. A managed team is created and
+// updated by manageTeam instead.
+func (r *JSMTeamReconciler) reconcileTeam(ctx context.Context, team *jsmv1beta1.JSMTeam, forced bool) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if isManaged(team) {
+		return r.manageTeam(ctx, team, forced)
+	}
+	team.Status.Plan = nil
+	meta.RemoveStatusCondition(&team.Status.Conditions, jsmv1beta1.ConditionRemoteSynced)
+
+	teamName := opsgenieTeamName(team)
 
 	var resolvedID string
+	reason := jsmv1beta1.ReasonResolved
 	switch {
 	case team.Spec.ID != "":
 		// if Spec.ID is provided, prefer it
 		resolvedID = team.Spec.ID
-	case team.Status.ID != "":
+		reason = jsmv1beta1.ReasonSpecifiedID
+	case team.Status.ID != "" && !forced:
 		// if status already has ID, reuse it
 		resolvedID = team.Status.ID
 	default:
+		if forced {
+			logger.Info("resync requested, looking the team up again", "annotation", jsmv1beta1.ReconcileAtAnnotation)
+			r.JSMClient.InvalidateTeamCache()
+		}
 		var err error
 		resolvedID, err = r.JSMClient.GetOpsgenieTeamIDByName(ctx, teamName)
 		if err != nil {
 			logger.Error(err, "unable to get team ID by name", "name", teamName)
-			return ctrl.Result{}, err
+			recordFailure(r.Recorder, team, opGetOpsgenieTeamIDByName, "", err)
+			failure := errorReason(err)
+			var notFound *jsmclient.NotFoundError
+			if errors.As(err, &notFound) {
+				// the team may have been created after the directory was loaded,
+				// make sure the retry sees a fresh copy
+				r.JSMClient.InvalidateTeamCache()
+				failure = jsmv1beta1.ReasonTeamNotFound
+			}
+			setCondition(&team.Status.Conditions, team.Generation, jsmv1beta1.ConditionTeamResolved, metav1.ConditionFalse, failure, err.Error())
+			return jsmErrorResult(err)
 		}
 	}
 
-	if team.Status.ID != resolvedID || team.Status.ObservedGeneration != team.ObjectMeta.Generation {
-		team.Status.ID = resolvedID
-		team.Status.ObservedGeneration = team.ObjectMeta.Generation
-
-		if err := r.Status().Update(ctx, &team); err != nil {
-			logger.Error(err, "unable to update JSMTeam status")
-			return ctrl.Result{}, err
-		}
-	}
+	team.Status.ID = resolvedID
+	setCondition(&team.Status.Conditions, team.Generation, jsmv1beta1.ConditionTeamResolved, metav1.ConditionTrue, reason,
+		fmt.Sprintf("Opsgenie team %q is %s", teamName, resolvedID))
 
-	logger.Info("successfully synced JSMTeam ID to status", "name", req.NamespacedName, "id", resolvedID, "teamName", teamName)
+	logger.Info("successfully synced JSMTeam ID to status", "name", client.ObjectKeyFromObject(team), "id", resolvedID, "teamName", teamName)
 	return ctrl.Result{}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *JSMTeamReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		WithOptions(controller.Options{
-			RateLimiter: workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](
-				20*time.Second,
-				5*time.Minute,
-			),
-		}).
+		WithOptions(r.Queue.options(DefaultTeamBaseBackoff, DefaultTeamMaxBackoff)).
 		For(&jsmv1beta1.JSMTeam{}).
 		WithEventFilter(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{})).
 		Named("jsmteam").
@@ -18,16 +18,20 @@ package controller
 
 import (
 	"context"
+	"fmt"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	jsmv1beta1 "github.com/artemlive/jsm-operator/api/v1beta1"
+	"github.com/artemlive/jsm-operator/internal/client/fake"
 )
 
 var _ = Describe("JSMTeam Controller", func() {
@@ -41,8 +45,10 @@ var _ = Describe("JSMTeam Controller", func() {
 			Namespace: "default", // TODO(user):Modify as needed
 		}
 		jsmteam := &jsmv1beta1.JSMTeam{}
+		var recorder *record.FakeRecorder
 
 		BeforeEach(func() {
+			recorder = record.NewFakeRecorder(10)
 			By("creating the custom resource for the Kind JSMTeam")
 			err := k8sClient.Get(ctx, typeNamespacedName, jsmteam)
 			if err != nil && errors.IsNotFound(err) {
@@ -54,6 +60,7 @@ var _ = Describe("JSMTeam Controller", func() {
 					// TODO(user): Specify other spec details if needed.
 				}
 				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+				jsmServer.AddTeam(resourceName)
 			}
 		})
 
@@ -69,8 +76,10 @@ var _ = Describe("JSMTeam Controller", func() {
 		It("should successfully reconcile the resource", func() {
 			By("Reconciling the created resource")
 			controllerReconciler := &JSMTeamReconciler{
-				Client: k8sClient,
-				Scheme: k8sClient.Scheme(),
+				Client:    k8sClient,
+				Scheme:    k8sClient.Scheme(),
+				JSMClient: jsmClient,
+				Recorder:  record.NewFakeRecorder(10),
 			}
 
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -79,6 +88,301 @@ var _ = Describe("JSMTeam Controller", func() {
 			Expect(err).NotTo(HaveOccurred())
 			// TODO(user): Add more specific assertions depending on your controller's reconciliation logic.
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
+			team := &jsmv1beta1.JSMTeam{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, team)).To(Succeed())
+			Expect(team.Status.ID).NotTo(BeEmpty())
+			Expect(meta.IsStatusConditionTrue(team.Status.Conditions, jsmv1beta1.ConditionTeamResolved)).To(BeTrue())
+			expectReady(team.Status.Conditions, team.Generation)
+		})
+
+		It("reconciles without an event recorder", func() {
+			key := types.NamespacedName{Name: "unrecorded-team", Namespace: "default"}
+			team := &jsmv1beta1.JSMTeam{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+				Spec:       jsmv1beta1.JSMTeamSpec{Name: "unrecorded-missing"},
+			}
+			Expect(k8sClient.Create(ctx, team)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, team)
+			controllerReconciler := &JSMTeamReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), JSMClient: jsmClient}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).To(HaveOccurred())
+			Expect(k8sClient.Get(ctx, key, team)).To(Succeed())
+			Expect(meta.FindStatusCondition(team.Status.Conditions, jsmv1beta1.ConditionTeamResolved).Reason).
+				To(Equal(jsmv1beta1.ReasonTeamNotFound))
+		})
+
+		It("honours the suspend and reconcile-at annotations", func() {
+			key := types.NamespacedName{Name: "paused-team", Namespace: "default"}
+			teamID := jsmServer.AddTeam("paused-team")
+			jsmClient.InvalidateTeamCache()
+			team := &jsmv1beta1.JSMTeam{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        key.Name,
+					Namespace:   key.Namespace,
+					Annotations: map[string]string{jsmv1beta1.SuspendAnnotation: "true"},
+				},
+				Spec: jsmv1beta1.JSMTeamSpec{Name: "paused-team"},
+			}
+			Expect(k8sClient.Create(ctx, team)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, team)
+			controllerReconciler := &JSMTeamReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), JSMClient: jsmClient, Recorder: recorder}
+
+			By("not resolving the team while suspended")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, key, team)).To(Succeed())
+			Expect(team.Status.ID).To(BeEmpty())
+			Expect(meta.IsStatusConditionTrue(team.Status.Conditions, jsmv1beta1.ConditionSuspended)).To(BeTrue())
+
+			By("resolving it once resumed")
+			delete(team.Annotations, jsmv1beta1.SuspendAnnotation)
+			Expect(k8sClient.Update(ctx, team)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, key, team)).To(Succeed())
+			Expect(team.Status.ID).To(Equal(teamID))
+			Expect(meta.FindStatusCondition(team.Status.Conditions, jsmv1beta1.ConditionSuspended)).To(BeNil())
+
+			By("looking the team up again when a resync is requested")
+			Expect(team.Status.ID).NotTo(BeEmpty())
+			team.Status.ID = "ari:stale"
+			Expect(k8sClient.Status().Update(ctx, team)).To(Succeed())
+			metav1.SetMetaDataAnnotation(&team.ObjectMeta, jsmv1beta1.ReconcileAtAnnotation, "now")
+			Expect(k8sClient.Update(ctx, team)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, key, team)).To(Succeed())
+			Expect(team.Status.ID).To(Equal(teamID))
+			Expect(team.Status.LastHandledReconcileAt).To(Equal("now"))
+		})
+
+		// recordedEvents drains the events recorded so far.
+		recordedEvents := func() []string {
+			var events []string
+			for {
+				select {
+				case event := <-recorder.Events:
+					events = append(events, event)
+				default:
+					return events
+				}
+			}
+		}
+
+		It("creates, updates and deletes a managed team", func() {
+			key := types.NamespacedName{Name: "managed-team", Namespace: "default"}
+			team := &jsmv1beta1.JSMTeam{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+				Spec: jsmv1beta1.JSMTeamSpec{
+					Name:             "Platform",
+					ManagementPolicy: jsmv1beta1.ManagementPolicyManage,
+					Description:      "Runs the platform",
+					Members: []jsmv1beta1.TeamMember{
+						{Username: "ada@example.com", Role: jsmv1beta1.TeamMemberRoleAdmin},
+						{Username: "bob@example.com"},
+					},
+					DeletionPolicy: jsmv1beta1.DeletionPolicyDelete,
+				},
+			}
+			Expect(k8sClient.Create(ctx, team)).To(Succeed())
+			controllerReconciler := &JSMTeamReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), JSMClient: jsmClient, Recorder: recorder}
+
+			By("creating the missing team")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, key, team)).To(Succeed())
+			Expect(team.Finalizers).To(ContainElement(jsmv1beta1.TeamFinalizer))
+			id := team.Status.ID
+			Expect(team.Status.CreatedID).To(Equal(id))
+			remote, ok := jsmServer.Team(id)
+			Expect(ok).To(BeTrue())
+			Expect(remote.Name).To(Equal("Platform"))
+			Expect(remote.Description).To(Equal("Runs the platform"))
+			Expect(remote.Members).To(ConsistOf(
+				fake.TeamMember{Username: "ada@example.com", Role: "admin"},
+				fake.TeamMember{Username: "bob@example.com", Role: "user"},
+			))
+			expectReady(team.Status.Conditions, team.Generation)
+			Expect(recordedEvents()).To(ConsistOf(HavePrefix(`Normal Created Created Opsgenie team "Platform" as ` + id)))
+
+			By("leaving a team that matches the spec alone")
+			updates := jsmServer.Calls("updateTeam")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(jsmServer.Calls("updateTeam")).To(Equal(updates))
+
+			By("applying a changed spec and reverting changes made in Opsgenie")
+			jsmServer.EditTeam(id, func(t *fake.Team) { t.Description = "Changed in the UI" })
+			Expect(k8sClient.Get(ctx, key, team)).To(Succeed())
+			team.Spec.Members = team.Spec.Members[:1]
+			Expect(k8sClient.Update(ctx, team)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			remote, _ = jsmServer.Team(id)
+			Expect(remote.Description).To(Equal("Runs the platform"))
+			Expect(remote.Members).To(ConsistOf(fake.TeamMember{Username: "ada@example.com", Role: "admin"}))
+			Expect(recordedEvents()).To(ConsistOf(HavePrefix("Normal Updated Updated Opsgenie team " + id + ": description, members")))
+
+			By("deleting the team together with the resource")
+			Expect(k8sClient.Get(ctx, key, team)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, team)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, key, team))).To(BeTrue())
+			_, ok = jsmServer.Team(id)
+			Expect(ok).To(BeFalse())
+			Expect(recordedEvents()).To(ConsistOf(HavePrefix("Normal Deleted Deleted Opsgenie team " + id)))
+		})
+
+		It("plans, observes and orphans a managed team", func() {
+			key := types.NamespacedName{Name: "shared-team", Namespace: "default"}
+			teamID := jsmServer.AddTeam("Shared")
+			members := []fake.TeamMember{{Username: "ada@example.com", Role: "admin"}, {Username: "bob@example.com", Role: "user"}}
+			jsmServer.EditTeam(teamID, func(t *fake.Team) { t.Members = members })
+			jsmClient.InvalidateTeamCache()
+			team := &jsmv1beta1.JSMTeam{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+				Spec: jsmv1beta1.JSMTeamSpec{
+					Name:             "Shared",
+					ManagementPolicy: jsmv1beta1.ManagementPolicyManage,
+					Description:      "Shared on-call",
+				},
+			}
+			Expect(k8sClient.Create(ctx, team)).To(Succeed())
+			controllerReconciler := &JSMTeamReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), JSMClient: jsmClient, Recorder: recorder, DryRun: true}
+
+			By("refusing to take over a team found by name")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).To(MatchError(reconcile.TerminalError(nil)))
+			Expect(k8sClient.Get(ctx, key, team)).To(Succeed())
+			Expect(team.Status.ID).To(BeEmpty())
+			condition := meta.FindStatusCondition(team.Status.Conditions, jsmv1beta1.ConditionTeamResolved)
+			Expect(condition.Reason).To(Equal(jsmv1beta1.ReasonAdoptionRefused))
+			Expect(meta.IsStatusConditionTrue(team.Status.Conditions, jsmv1beta1.ConditionStalled)).To(BeTrue())
+			Expect(recordedEvents()).To(ConsistOf(HavePrefix("Warning AdoptionRefused")))
+
+			By("only planning the update of the pinned team in a dry run")
+			team.Spec.ID = teamID
+			Expect(k8sClient.Update(ctx, team)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, key, team)).To(Succeed())
+			Expect(team.Status.ID).To(Equal(teamID))
+			Expect(team.Status.Plan).To(Equal([]string{"Update Opsgenie team " + teamID + ": description"}))
+			condition = meta.FindStatusCondition(team.Status.Conditions, jsmv1beta1.ConditionRemoteSynced)
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(jsmv1beta1.ReasonDryRun))
+			Expect(recordedEvents()).To(ConsistOf(And(
+				HavePrefix("Normal Planned Dry run, planned: Update Opsgenie team"),
+				HaveSuffix(fmt.Sprintf("map[%s:%s %s:UpdateOpsgenieTeam]", jsmv1beta1.EventAnnotationARI, teamID, jsmv1beta1.EventAnnotationOperation)),
+			)))
+			remote, _ := jsmServer.Team(teamID)
+			Expect(remote.Description).To(BeEmpty())
+
+			By("only comparing a read-only team")
+			controllerReconciler.DryRun = false
+			metav1.SetMetaDataAnnotation(&team.ObjectMeta, jsmv1beta1.ReadOnlyAnnotation, "true")
+			Expect(k8sClient.Update(ctx, team)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).To(HaveOccurred())
+			Expect(k8sClient.Get(ctx, key, team)).To(Succeed())
+			Expect(team.Status.Plan).To(BeEmpty())
+			condition = meta.FindStatusCondition(team.Status.Conditions, jsmv1beta1.ConditionRemoteSynced)
+			Expect(condition.Reason).To(Equal(jsmv1beta1.ReasonReadOnly))
+			Expect(condition.Message).To(HaveSuffix("not corrected while read-only: description"))
+			Expect(meta.IsStatusConditionTrue(team.Status.Conditions, jsmv1beta1.ConditionStalled)).To(BeTrue())
+
+			By("applying the spec to the pinned team once writable")
+			delete(team.Annotations, jsmv1beta1.ReadOnlyAnnotation)
+			Expect(k8sClient.Update(ctx, team)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			remote, _ = jsmServer.Team(teamID)
+			Expect(remote.Description).To(Equal("Shared on-call"))
+			Expect(remote.Members).To(Equal(members), "members aren't managed without spec.members")
+
+			By("leaving the team behind on deletion by default")
+			deletes := jsmServer.Calls("deleteTeam")
+			Expect(k8sClient.Get(ctx, key, team)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, team)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, key, team))).To(BeTrue())
+			_, ok := jsmServer.Team(teamID)
+			Expect(ok).To(BeTrue())
+			Expect(jsmServer.Calls("deleteTeam")).To(Equal(deletes))
+		})
+
+		It("takes over the team of a create whose status write failed", func() {
+			key := types.NamespacedName{Name: "crash-team", Namespace: "default"}
+			team := &jsmv1beta1.JSMTeam{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+				Spec: jsmv1beta1.JSMTeamSpec{
+					Name:             "Crash",
+					ManagementPolicy: jsmv1beta1.ManagementPolicyManage,
+				},
+			}
+			Expect(k8sClient.Create(ctx, team)).To(Succeed())
+			controllerReconciler := &JSMTeamReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), JSMClient: jsmClient, Recorder: recorder}
+			crashing := &JSMTeamReconciler{
+				Client:    &failingStatusClient{Client: k8sClient, failAt: 2},
+				Scheme:    k8sClient.Scheme(),
+				JSMClient: jsmClient,
+				Recorder:  recorder,
+			}
+
+			By("keeping the pending create when the ARI of the new team isn't stored")
+			creates := jsmServer.Calls("createTeam")
+			_, err := crashing.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).To(HaveOccurred())
+			Expect(jsmServer.Calls("createTeam")).To(Equal(creates + 1))
+			Expect(k8sClient.Get(ctx, key, team)).To(Succeed())
+			Expect(team.Status.CreatedID).To(BeEmpty())
+			Expect(team.Status.PendingCreate).NotTo(BeNil())
+			Expect(team.Status.PendingCreate.Name).To(Equal("Crash"))
+			recordedEvents()
+
+			By("taking the team with the recorded name over instead of refusing it")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(jsmServer.Calls("createTeam")).To(Equal(creates + 1))
+			Expect(k8sClient.Get(ctx, key, team)).To(Succeed())
+			id := team.Status.ID
+			Expect(team.Status.CreatedID).To(Equal(id))
+			Expect(team.Status.PendingCreate).To(BeNil())
+			remote, ok := jsmServer.Team(id)
+			Expect(ok).To(BeTrue())
+			Expect(remote.Name).To(Equal("Crash"))
+			expectReady(team.Status.Conditions, team.Generation)
+			Expect(recordedEvents()).To(ConsistOf(HavePrefix(`Normal Created Recovered Opsgenie team "Crash" created by an interrupted attempt as ` + id)))
+
+			Expect(k8sClient.Delete(ctx, team)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("reports a team that doesn't exist in Opsgenie", func() {
+			key := types.NamespacedName{Name: "missing-team", Namespace: "default"}
+			team := &jsmv1beta1.JSMTeam{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+				Spec:       jsmv1beta1.JSMTeamSpec{Name: "no-such-team"},
+			}
+			Expect(k8sClient.Create(ctx, team)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, team)
+
+			controllerReconciler := &JSMTeamReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), JSMClient: jsmClient, Recorder: recorder}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).To(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, key, team)).To(Succeed())
+			condition := meta.FindStatusCondition(team.Status.Conditions, jsmv1beta1.ConditionTeamResolved)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(jsmv1beta1.ReasonTeamNotFound))
+			Expect(meta.IsStatusConditionFalse(team.Status.Conditions, jsmv1beta1.ConditionReady)).To(BeTrue())
+			Expect(meta.IsStatusConditionTrue(team.Status.Conditions, jsmv1beta1.ConditionReconciling)).To(BeTrue())
+			Expect(recorder.Events).To(Receive(HavePrefix("Warning ResolveFailed GetOpsgenieTeamIDByName failed")))
 		})
 	})
 })
@@ -0,0 +1,102 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	jsmv1beta1 "github.com/artemlive/jsm-operator/api/v1beta1"
+	jsmclient "github.com/artemlive/jsm-operator/internal/client"
+	"github.com/go-logr/logr"
+)
+
+// writeAheadCreate records the pending create in the status before the JSM
+// service is created. Should the reconcile die before the ID of the new
+// service is stored, the next one finds it by name instead of creating it
+// again. A retry of the same create keeps the time of the first attempt.
+func (r *JSMServiceReconciler) writeAheadCreate(ctx context.Context, service *jsmv1beta1.JSMService, name string) error {
+	if pending := service.Status.PendingCreate; pending == nil || pending.Name != name {
+		service.Status.PendingCreate = &jsmv1beta1.PendingCreate{
+			Name: name,
+			Time: metav1.Now(),
+		}
+	}
+	return r.Status().Update(ctx, service)
+}
+
+// resumeCreate finishes a create that was interrupted before the ID of the new
+// service was stored. The recovery goes by name only: a service with the
+// recorded name was most likely created by the interrupted attempt, so it is
+// taken over if checkAdoption accepts it as such. Otherwise the service is
+// created again.
+func (r *JSMServiceReconciler) resumeCreate(ctx context.Context, service *jsmv1beta1.JSMService, teams []jsmv1beta1.ServiceTeamStatus, tier *jsmclient.Tier, pending *jsmv1beta1.PendingCreate, log logr.Logger) (ctrl.Result, error) {
+	found, err := r.JSMClient.GetServiceByName(ctx, pending.Name)
+	if err != nil {
+		log.Error(err, "Failed to look up the JSMService of an interrupted create", "name", pending.Name)
+		return r.remoteSyncFailed(service, opGetServiceByName, "", err)
+	}
+	if found == nil {
+		log.Info("Interrupted create left no JSMService behind, creating it again", "name", pending.Name)
+		return r.createNewService(ctx, service, teams, tier, getServiceName(service), log)
+	}
+
+	jsmService, err := r.JSMClient.GetServiceByID(ctx, found.ID)
+	if err != nil {
+		log.Error(err, "Failed to fetch the JSMService of an interrupted create", "id", found.ID)
+		return r.remoteSyncFailed(service, opGetServiceByID, found.ID, err)
+	}
+	if err := r.checkAdoption(service, teams, jsmService, jsmv1beta1.AdoptionSourcePendingCreate); err != nil {
+		return r.refuseAdoption(service, jsmService.ID, err, log)
+	}
+
+	service.Status.ID = jsmService.ID
+	service.Status.Name = jsmService.Name
+	service.Status.Revision = jsmService.Revision
+	service.Status.TierID = jsmService.TierID
+	service.Status.TierLevel = jsmService.TierLevel
+	service.Status.TierName = jsmService.TierName
+	service.Status.Adoption = nil
+	service.Status.PendingCreate = nil
+	markSynced(service)
+	r.setCondition(service, jsmv1beta1.ConditionRemoteSynced, metav1.ConditionTrue, jsmv1beta1.ReasonCreated,
+		fmt.Sprintf("Created JSM service %s", jsmService.ID))
+	recordEvent(r.Recorder, service, corev1.EventTypeNormal, jsmv1beta1.ReasonCreated, opGetServiceByName, jsmService.ID,
+		"Recovered JSM service %s created by an interrupted attempt", jsmService.ID)
+	log.Info("Recovered the JSMService of an interrupted create", "id", jsmService.ID, "name", pending.Name)
+
+	return r.applySpecToExisting(ctx, service, teams, tier, jsmService, log)
+}
+
+// createMayHaveSucceeded reports whether a failed create might still have
+// created the service, e.g. because the response got lost. An error JSM
+// answered with proves it didn't.
+func createMayHaveSucceeded(err error) bool {
+	var (
+		validation    *jsmclient.ValidationError
+		alreadyExists *jsmclient.AlreadyExistsError
+		authErr       *jsmclient.AuthError
+		rateLimited   *jsmclient.RateLimitedError
+	)
+	return !errors.As(err, &validation) && !errors.As(err, &alreadyExists) &&
+		!errors.As(err, &authErr) && !errors.As(err, &rateLimited)
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	jsmv1beta1 "github.com/artemlive/jsm-operator/api/v1beta1"
+	jsmclient "github.com/artemlive/jsm-operator/internal/client"
+	"github.com/go-logr/logr"
+)
+
+// planChanges works out the JSM operations the reconcile would issue for the
+// spec and reports them instead of issuing them. Only queries are sent to JSM,
+// so the plan reflects the current remote state. Nothing the plan depends on,
+// such as the ID of an adoptable service, is stored.
+func (r *JSMServiceReconciler) planChanges(ctx context.Context, service *jsmv1beta1.JSMService, teams []jsmv1beta1.ServiceTeamStatus, tier *jsmclient.Tier, log logr.Logger) (ctrl.Result, error) {
+	var plan []string
+	var remote *jsmclient.Service
+	var err error
+	switch id := service.Status.ID; {
+	case id == "" && service.Spec.ExternalID != "":
+		remote, err = r.JSMClient.GetServiceByID(ctx, service.Spec.ExternalID)
+		if err != nil {
+			return r.remoteSyncFailed(service, opGetServiceByID, service.Spec.ExternalID, err)
+		}
+		if err := r.checkAdoption(service, teams, remote, jsmv1beta1.AdoptionSourceExternalID); err != nil {
+			return r.refuseAdoption(service, remote.ID, err, log)
+		}
+		plan = append(plan, fmt.Sprintf("Adopt JSM service %s (%q) by externalID", remote.ID, remote.Name))
+
+	case id == "":
+		// an interrupted create is resumed by the name it recorded
+		name, pending := getServiceName(service), service.Status.PendingCreate
+		if pending != nil {
+			name = pending.Name
+		}
+		found, err := r.JSMClient.GetServiceByName(ctx, name)
+		if err != nil {
+			return r.remoteSyncFailed(service, opGetServiceByName, "", err)
+		}
+		if found == nil {
+			plan = append(plan, createPlan(service, teams, tier)...)
+			break
+		}
+		remote, err = r.JSMClient.GetServiceByID(ctx, found.ID)
+		if err != nil {
+			return r.remoteSyncFailed(service, opGetServiceByID, found.ID, err)
+		}
+		source := jsmv1beta1.AdoptionSourceName
+		if pending != nil {
+			source = jsmv1beta1.AdoptionSourcePendingCreate
+		}
+		if err := r.checkAdoption(service, teams, remote, source); err != nil {
+			return r.refuseAdoption(service, remote.ID, err, log)
+		}
+		plan = append(plan, fmt.Sprintf("Adopt JSM service %s (%q) by %s", remote.ID, remote.Name, strings.ToLower(string(source))))
+
+	default:
+		remote, err = r.JSMClient.GetServiceByID(ctx, id)
+		var notFound *jsmclient.NotFoundError
+		if errors.As(err, &notFound) {
+			plan = append(plan, fmt.Sprintf("Recreate JSM service %s, it no longer exists", id))
+			plan = append(plan, createPlan(service, teams, tier)...)
+			break
+		}
+		if err != nil {
+			return r.remoteSyncFailed(service, opGetServiceByID, id, err)
+		}
+	}
+	if remote != nil {
+		plan = append(plan, updatePlan(service, teams, tier, remote)...)
+	}
+
+	r.reportPlan(service, teams, remote, plan, log)
+	return ctrl.Result{}, nil
+}
+
+// createPlan lists the operations that create the service and link it with
+// its owner teams.
+func createPlan(service *jsmv1beta1.JSMService, teams []jsmv1beta1.ServiceTeamStatus, tier *jsmclient.Tier) []string {
+	responders := "none"
+	if ids := responderIDs(teams); len(ids) > 0 {
+		responders = strings.Join(ids, ", ")
+	}
+	plan := []string{fmt.Sprintf("Create JSM service %q with tier %q and responders %s", getServiceName(service), tier.Name, responders)}
+	for _, teamID := range ownerIDs(teams) {
+		plan = append(plan, fmt.Sprintf("Link the new JSM service with Opsgenie team %s", teamID))
+	}
+	return plan
+}
+
+// updatePlan lists the operations that bring the existing JSM service in line
+// with the spec.
+func updatePlan(service *jsmv1beta1.JSMService, teams []jsmv1beta1.ServiceTeamStatus, tier *jsmclient.Tier, remote *jsmclient.Service) []string {
+	var plan []string
+	drifted := slices.DeleteFunc(serviceDrift(service, teams, tier, remote), func(field string) bool {
+		return !updatable(field)
+	})
+	if len(drifted) > 0 {
+		plan = append(plan, fmt.Sprintf("Update JSM service %s: %s", remote.ID, strings.Join(drifted, ", ")))
+	}
+
+	owners := ownerIDs(teams)
+	linked := make([]string, 0, len(remote.TeamRelationships))
+	for _, rel := range remote.TeamRelationships {
+		linked = append(linked, rel.TeamID)
+	}
+	for _, teamID := range owners {
+		if !slices.Contains(linked, teamID) {
+			plan = append(plan, fmt.Sprintf("Link JSM service %s with Opsgenie team %s", remote.ID, teamID))
+		}
+	}
+	for _, teamID := range linked {
+		if !slices.Contains(owners, teamID) {
+			plan = append(plan, fmt.Sprintf("Unlink JSM service %s from Opsgenie team %s", remote.ID, teamID))
+		}
+	}
+	return plan
+}
+
+// reportPlan records the plan in the status and, if it changed, as an event.
+// The event is about updating remote, or creating a service if there is none.
+// A pending plan keeps RemoteSynced False, an empty one means the JSM service
+// already matches the spec.
+func (r *JSMServiceReconciler) reportPlan(service *jsmv1beta1.JSMService, teams []jsmv1beta1.ServiceTeamStatus, remote *jsmclient.Service, plan []string, log logr.Logger) {
+	markSynced(service)
+	changed := !slices.Equal(plan, service.Status.Plan)
+	service.Status.Plan = plan
+
+	if len(plan) == 0 {
+		r.setCondition(service, jsmv1beta1.ConditionRemoteSynced, metav1.ConditionTrue, jsmv1beta1.ReasonInSync,
+			"Dry run, the JSM service matches the spec")
+		r.setCondition(service, jsmv1beta1.ConditionRelationshipLinked, metav1.ConditionTrue, jsmv1beta1.ReasonLinked, linkedMessage(ownerIDs(teams)))
+		return
+	}
+
+	log.Info("Dry run, not applying the planned changes", "plan", plan)
+	message := fmt.Sprintf("Dry run, planned: %s", strings.Join(plan, "; "))
+	r.setCondition(service, jsmv1beta1.ConditionRemoteSynced, metav1.ConditionFalse, jsmv1beta1.ReasonDryRun, message)
+	if changed {
+		op, ari := opCreateService, ""
+		if remote != nil {
+			op, ari = opUpdateService, remote.ID
+		}
+		recordEvent(r.Recorder, service, corev1.EventTypeNormal, jsmv1beta1.EventPlanned, op, ari, "%s", message)
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Default backoff of a resource whose reconcile failed. Services back off
+// like any controller-runtime controller, teams start slower as a missing
+// team usually has to be created by hand.
+const (
+	DefaultServiceBaseBackoff = 5 * time.Millisecond
+	DefaultServiceMaxBackoff  = 1000 * time.Second
+	DefaultTeamBaseBackoff    = 20 * time.Second
+	DefaultTeamMaxBackoff     = 5 * time.Minute
+)
+
+// Default overall rate at which queued resources are reconciled.
+const (
+	DefaultQueueQPS   = 10
+	DefaultQueueBurst = 100
+)
+
+// QueueConfig tunes how a controller works off its queue. Zero values fall
+// back to the defaults of the controller.
+type QueueConfig struct {
+	// MaxConcurrentReconciles is the number of resources reconciled at once.
+	MaxConcurrentReconciles int
+	// BaseBackoff and MaxBackoff bound the exponential delay before a failed
+	// resource is reconciled again.
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// Limiter caps the rate at which resources are taken off the queue. It
+	// may be shared by several controllers, nil means no limit.
+	Limiter *rate.Limiter
+}
+
+// NewQueueLimiter returns a limiter for QueueConfig, or nil if qps isn't
+// positive.
+func NewQueueLimiter(qps float64, burst int) *rate.Limiter {
+	if qps <= 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(qps), burst)
+}
+
+// options returns the controller options for the config, with the given
+// backoff as default.
+func (c QueueConfig) options(baseBackoff, maxBackoff time.Duration) controller.Options {
+	if c.BaseBackoff > 0 {
+		baseBackoff = c.BaseBackoff
+	}
+	if c.MaxBackoff > 0 {
+		maxBackoff = c.MaxBackoff
+	}
+	limiters := []workqueue.TypedRateLimiter[reconcile.Request]{
+		workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](baseBackoff, maxBackoff),
+	}
+	if c.Limiter != nil {
+		limiters = append(limiters, &workqueue.TypedBucketRateLimiter[reconcile.Request]{Limiter: c.Limiter})
+	}
+	return controller.Options{
+		MaxConcurrentReconciles: c.MaxConcurrentReconciles,
+		RateLimiter:             workqueue.NewTypedMaxOfRateLimiter(limiters...),
+	}
+}
@@ -33,6 +33,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	jsmv1beta1 "github.com/artemlive/jsm-operator/api/v1beta1"
+	jsmclient "github.com/artemlive/jsm-operator/internal/client"
+	"github.com/artemlive/jsm-operator/internal/client/fake"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -45,6 +47,10 @@ var (
 	testEnv   *envtest.Environment
 	cfg       *rest.Config
 	k8sClient client.Client
+
+	// jsmServer is the in-process JSM backend the reconcilers talk to
+	jsmServer *fake.Server
+	jsmClient *jsmclient.JSMClient
 )
 
 func TestControllers(t *testing.T) {
@@ -83,11 +89,25 @@ var _ = BeforeSuite(func() {
 	k8sClient, err = client.New(cfg, client.Options{Scheme: scheme.Scheme})
 	Expect(err).NotTo(HaveOccurred())
 	Expect(k8sClient).NotTo(BeNil())
+
+	By("starting the fake JSM backend")
+	jsmServer = fake.NewServer("test-cloud")
+	jsmClient, err = jsmclient.NewJSMClient(jsmclient.JSMConfig{
+		GraphQLURL: jsmServer.URL,
+		RestURL:    jsmServer.URL,
+		Token:      "token",
+		Username:   "user",
+		CloudID:    "test-cloud",
+	})
+	Expect(err).NotTo(HaveOccurred())
 })
 
 var _ = AfterSuite(func() {
 	By("tearing down the test environment")
 	cancel()
+	if jsmServer != nil {
+		jsmServer.Close()
+	}
 	err := testEnv.Stop()
 	Expect(err).NotTo(HaveOccurred())
 })
@@ -0,0 +1,396 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	jsmv1beta1 "github.com/artemlive/jsm-operator/api/v1beta1"
+	jsmclient "github.com/artemlive/jsm-operator/internal/client"
+)
+
+// isManaged reports whether the operator owns the Opsgenie team of the
+// JSMTeam rather than only looking it up.
+func isManaged(team *jsmv1beta1.JSMTeam) bool {
+	return team.Spec.ManagementPolicy == jsmv1beta1.ManagementPolicyManage
+}
+
+// manageTeam brings the Opsgenie team in line with the spec and creates it if
+// it doesn't exist. Only the team pinned by spec.id or the one the operator
+// created is managed: an existing team with the name of the spec may belong to
+// someone else, so it is refused rather than taken over, unless an
+// interrupted create recorded that name. A read-only team is only compared
+// with the spec, a dry run only plans the changes.
+func (r *JSMTeamReconciler) manageTeam(ctx context.Context, team *jsmv1beta1.JSMTeam, forced bool) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	// a read-only team is observed rather than planned for
+	readOnly := isReadOnly(team)
+	dryRun := r.DryRun && !readOnly
+	if !dryRun {
+		team.Status.Plan = nil
+	}
+
+	name := opsgenieTeamName(team)
+	reason := jsmv1beta1.ReasonResolved
+	id := team.Status.CreatedID
+	if team.Spec.ID != "" {
+		id = team.Spec.ID
+		reason = jsmv1beta1.ReasonSpecifiedID
+	}
+	pending := team.Status.PendingCreate
+	if id == "" {
+		// the directory may predate the team an interrupted create made
+		if forced || pending != nil {
+			r.JSMClient.InvalidateTeamCache()
+		}
+		found, err := r.JSMClient.GetOpsgenieTeamIDByName(ctx, name)
+		var notFound *jsmclient.NotFoundError
+		if err != nil && !errors.As(err, &notFound) {
+			logger.Error(err, "unable to get team ID by name", "name", name)
+			return r.teamResolveFailed(team, opGetOpsgenieTeamIDByName, "", err)
+		}
+		if found != "" && (pending == nil || pending.Name != name) {
+			return r.refuseTeamTakeover(ctx, team, name, found)
+		}
+		if found != "" {
+			id, reason = found, jsmv1beta1.ReasonCreated
+			team.Status.CreatedID = found
+			team.Status.PendingCreate = nil
+			recordEvent(r.Recorder, team, corev1.EventTypeNormal, jsmv1beta1.ReasonCreated, opGetOpsgenieTeamIDByName, found,
+				"Recovered Opsgenie team %q created by an interrupted attempt as %s", name, found)
+			logger.Info("recovered the Opsgenie team of an interrupted create", "name", name, "id", found)
+		}
+	}
+
+	var remote *jsmclient.OpsgenieTeam
+	if id != "" {
+		var err error
+		remote, err = r.JSMClient.GetOpsgenieTeam(ctx, id)
+		var notFound *jsmclient.NotFoundError
+		switch {
+		case errors.As(err, &notFound) && team.Spec.ID == "":
+			logger.Info("Opsgenie team no longer exists, creating it again", "id", id)
+			team.Status.CreatedID = ""
+			remote = nil
+		case err != nil:
+			logger.Error(err, "unable to get Opsgenie team", "id", id)
+			return r.teamResolveFailed(team, opGetOpsgenieTeam, id, err)
+		}
+	}
+
+	desired := desiredTeam(team)
+	if remote == nil {
+		return r.createTeam(ctx, team, desired, readOnly, dryRun)
+	}
+
+	team.Status.ID = remote.ID
+	setCondition(&team.Status.Conditions, team.Generation, jsmv1beta1.ConditionTeamResolved, metav1.ConditionTrue, reason,
+		fmt.Sprintf("Opsgenie team %q is %s", name, remote.ID))
+	return r.updateTeam(ctx, team, desired, remote, readOnly, dryRun)
+}
+
+// createTeam creates the Opsgenie team of the spec and stores its ARI.
+func (r *JSMTeamReconciler) createTeam(ctx context.Context, team *jsmv1beta1.JSMTeam, desired *jsmclient.OpsgenieTeamRequest, readOnly, dryRun bool) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	switch {
+	case readOnly:
+		err := errors.New("read-only, the Opsgenie team is not created")
+		logger.Info("JSMTeam is read-only and has no Opsgenie team to observe", "name", desired.Name)
+		setCondition(&team.Status.Conditions, team.Generation, jsmv1beta1.ConditionTeamResolved, metav1.ConditionFalse, jsmv1beta1.ReasonReadOnly, err.Error())
+		return ctrl.Result{}, reconcile.TerminalError(err)
+	case dryRun:
+		setCondition(&team.Status.Conditions, team.Generation, jsmv1beta1.ConditionTeamResolved, metav1.ConditionFalse, jsmv1beta1.ReasonDryRun,
+			fmt.Sprintf("Dry run, Opsgenie team %q doesn't exist yet", desired.Name))
+		r.reportTeamPlan(ctx, team, nil, []string{fmt.Sprintf("Create Opsgenie team %q with %s", desired.Name, describeMembers(desired.Members))})
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.writeAheadCreate(ctx, team, desired.Name); err != nil {
+		logger.Error(err, "unable to record the pending create")
+		return ctrl.Result{}, err
+	}
+
+	created, err := r.JSMClient.CreateOpsgenieTeam(ctx, desired)
+	if err != nil {
+		logger.Error(err, "unable to create Opsgenie team", "name", desired.Name)
+		recordFailure(r.Recorder, team, opCreateOpsgenieTeam, "", err)
+		if !createMayHaveSucceeded(err) {
+			team.Status.PendingCreate = nil
+		}
+		var exists *jsmclient.AlreadyExistsError
+		if errors.As(err, &exists) {
+			// created elsewhere after the directory was loaded, the retry
+			// finds and refuses it
+			r.JSMClient.InvalidateTeamCache()
+		}
+		setCondition(&team.Status.Conditions, team.Generation, jsmv1beta1.ConditionRemoteSynced, metav1.ConditionFalse, errorReason(err), err.Error())
+		return jsmErrorResult(err)
+	}
+
+	team.Status.ID = created.ID
+	team.Status.CreatedID = created.ID
+	team.Status.PendingCreate = nil
+	setCondition(&team.Status.Conditions, team.Generation, jsmv1beta1.ConditionTeamResolved, metav1.ConditionTrue, jsmv1beta1.ReasonCreated,
+		fmt.Sprintf("Opsgenie team %q is %s", desired.Name, created.ID))
+	setCondition(&team.Status.Conditions, team.Generation, jsmv1beta1.ConditionRemoteSynced, metav1.ConditionTrue, jsmv1beta1.ReasonCreated,
+		fmt.Sprintf("Created Opsgenie team %s", created.ID))
+	recordEvent(r.Recorder, team, corev1.EventTypeNormal, jsmv1beta1.ReasonCreated, opCreateOpsgenieTeam, created.ID,
+		"Created Opsgenie team %q as %s", desired.Name, created.ID)
+	logger.Info("created Opsgenie team", "name", desired.Name, "id", created.ID)
+	return ctrl.Result{}, nil
+}
+
+// writeAheadCreate records the pending create in the status before the
+// Opsgenie team is created. Should the reconcile die before the ARI of the new
+// team is stored, the next one takes the team with the recorded name over
+// instead of refusing it.
+func (r *JSMTeamReconciler) writeAheadCreate(ctx context.Context, team *jsmv1beta1.JSMTeam, name string) error {
+	if pending := team.Status.PendingCreate; pending == nil || pending.Name != name {
+		team.Status.PendingCreate = &jsmv1beta1.PendingCreate{Name: name, Time: metav1.Now()}
+	}
+	return r.Status().Update(ctx, team)
+}
+
+// updateTeam applies the name, description and members of the spec to the
+// existing Opsgenie team if any of them differ.
+func (r *JSMTeamReconciler) updateTeam(ctx context.Context, team *jsmv1beta1.JSMTeam, desired *jsmclient.OpsgenieTeamRequest, remote *jsmclient.OpsgenieTeam, readOnly, dryRun bool) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	drifted := teamDrift(desired, remote)
+	switch {
+	case dryRun:
+		var plan []string
+		if len(drifted) > 0 {
+			plan = append(plan, fmt.Sprintf("Update Opsgenie team %s: %s", remote.ID, strings.Join(drifted, ", ")))
+		}
+		r.reportTeamPlan(ctx, team, remote, plan)
+		return ctrl.Result{}, nil
+	case len(drifted) == 0:
+		setCondition(&team.Status.Conditions, team.Generation, jsmv1beta1.ConditionRemoteSynced, metav1.ConditionTrue, jsmv1beta1.ReasonInSync,
+			"The Opsgenie team matches the spec")
+		return ctrl.Result{}, nil
+	case readOnly:
+		err := fmt.Errorf("differs from the spec, not corrected while read-only: %s", strings.Join(drifted, ", "))
+		logger.Info("JSMTeam is read-only, not updating the Opsgenie team", "id", remote.ID, "fields", drifted)
+		setCondition(&team.Status.Conditions, team.Generation, jsmv1beta1.ConditionRemoteSynced, metav1.ConditionFalse, jsmv1beta1.ReasonReadOnly, err.Error())
+		return ctrl.Result{}, reconcile.TerminalError(err)
+	}
+
+	if err := r.JSMClient.UpdateOpsgenieTeam(ctx, remote.ID, desired); err != nil {
+		logger.Error(err, "unable to update Opsgenie team", "id", remote.ID)
+		recordFailure(r.Recorder, team, opUpdateOpsgenieTeam, remote.ID, err)
+		setCondition(&team.Status.Conditions, team.Generation, jsmv1beta1.ConditionRemoteSynced, metav1.ConditionFalse, errorReason(err), err.Error())
+		return jsmErrorResult(err)
+	}
+
+	setCondition(&team.Status.Conditions, team.Generation, jsmv1beta1.ConditionRemoteSynced, metav1.ConditionTrue, jsmv1beta1.ReasonSynced,
+		fmt.Sprintf("Updated the Opsgenie team: %s", strings.Join(drifted, ", ")))
+	recordEvent(r.Recorder, team, corev1.EventTypeNormal, jsmv1beta1.EventUpdated, opUpdateOpsgenieTeam, remote.ID,
+		"Updated Opsgenie team %s: %s", remote.ID, strings.Join(drifted, ", "))
+	logger.Info("updated Opsgenie team", "id", remote.ID, "fields", drifted)
+	return ctrl.Result{}, nil
+}
+
+// refuseTeamTakeover reports an existing Opsgenie team with the name of the
+// spec that the operator didn't create. It stalls the team until the spec
+// pins it by spec.id or names another team.
+func (r *JSMTeamReconciler) refuseTeamTakeover(ctx context.Context, team *jsmv1beta1.JSMTeam, name, id string) (ctrl.Result, error) {
+	err := fmt.Errorf("Opsgenie team %q already exists as %s and wasn't created by the operator, set spec.id to manage it", name, id)
+	log.FromContext(ctx).Error(err, "refusing to take over Opsgenie team", "id", id)
+	setCondition(&team.Status.Conditions, team.Generation, jsmv1beta1.ConditionTeamResolved, metav1.ConditionFalse, jsmv1beta1.ReasonAdoptionRefused, err.Error())
+	recordEvent(r.Recorder, team, corev1.EventTypeWarning, jsmv1beta1.ReasonAdoptionRefused, opGetOpsgenieTeamIDByName, id, "%s", err.Error())
+	return ctrl.Result{}, reconcile.TerminalError(err)
+}
+
+// teamResolveFailed reports a failed lookup of a managed team.
+func (r *JSMTeamReconciler) teamResolveFailed(team *jsmv1beta1.JSMTeam, op, id string, err error) (ctrl.Result, error) {
+	recordFailure(r.Recorder, team, op, id, err)
+	setCondition(&team.Status.Conditions, team.Generation, jsmv1beta1.ConditionTeamResolved, metav1.ConditionFalse, errorReason(err), err.Error())
+	return jsmErrorResult(err)
+}
+
+// reportTeamPlan records the plan in the status and, if it changed, as an
+// event. The event is about updating remote, or creating the team if there is
+// none. A pending plan keeps RemoteSynced False, an empty one means the
+// Opsgenie team already matches the spec.
+func (r *JSMTeamReconciler) reportTeamPlan(ctx context.Context, team *jsmv1beta1.JSMTeam, remote *jsmclient.OpsgenieTeam, plan []string) {
+	changed := !slices.Equal(plan, team.Status.Plan)
+	team.Status.Plan = plan
+
+	if len(plan) == 0 {
+		setCondition(&team.Status.Conditions, team.Generation, jsmv1beta1.ConditionRemoteSynced, metav1.ConditionTrue, jsmv1beta1.ReasonInSync,
+			"Dry run, the Opsgenie team matches the spec")
+		return
+	}
+
+	log.FromContext(ctx).Info("Dry run, not applying the planned changes", "plan", plan)
+	message := fmt.Sprintf("Dry run, planned: %s", strings.Join(plan, "; "))
+	setCondition(&team.Status.Conditions, team.Generation, jsmv1beta1.ConditionRemoteSynced, metav1.ConditionFalse, jsmv1beta1.ReasonDryRun, message)
+	if changed {
+		op, ari := opCreateOpsgenieTeam, ""
+		if remote != nil {
+			op, ari = opUpdateOpsgenieTeam, remote.ID
+		}
+		recordEvent(r.Recorder, team, corev1.EventTypeNormal, jsmv1beta1.EventPlanned, op, ari, "%s", message)
+	}
+}
+
+// handleTeamDeletion deletes the managed Opsgenie team unless the deletion
+// policy orphans it, then releases the finalizer. A failed deletion is
+// reported on the DeletionFailed condition and retried, switching the policy
+// to Orphan releases the resource. A suspended or read-only team and a dry run
+// always orphan it.
+func (r *JSMTeamReconciler) handleTeamDeletion(ctx context.Context, team *jsmv1beta1.JSMTeam) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	if !controllerutil.ContainsFinalizer(team, jsmv1beta1.TeamFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	id := team.Status.ID
+	policy := teamDeletionPolicy(team)
+	if policy == jsmv1beta1.DeletionPolicyDelete && (isSuspended(team) || isReadOnly(team)) {
+		logger.Info("Not deleting the Opsgenie team of a suspended or read-only resource", "id", id)
+		policy = jsmv1beta1.DeletionPolicyOrphan
+	}
+	if policy == jsmv1beta1.DeletionPolicyDelete && id != "" && r.DryRun {
+		logger.Info("Dry run, not deleting Opsgenie team", "id", id)
+		recordEvent(r.Recorder, team, corev1.EventTypeNormal, jsmv1beta1.EventPlanned, opDeleteOpsgenieTeam, id,
+			"Dry run, planned: Delete Opsgenie team %s", id)
+		policy = jsmv1beta1.DeletionPolicyOrphan
+	}
+	if policy == jsmv1beta1.DeletionPolicyDelete && id != "" {
+		err := r.JSMClient.DeleteOpsgenieTeam(ctx, id)
+		var notFound *jsmclient.NotFoundError
+		if err != nil && !errors.As(err, &notFound) {
+			logger.Error(err, "unable to delete Opsgenie team", "id", id)
+			recordFailure(r.Recorder, team, opDeleteOpsgenieTeam, id, err)
+			result, resultErr := jsmErrorResult(err)
+			setCondition(&team.Status.Conditions, team.Generation, jsmv1beta1.ConditionDeletionFailed, metav1.ConditionTrue, jsmv1beta1.ReasonDeleteFailed, err.Error())
+			setNotReady(&team.Status.Conditions, team.Generation, jsmv1beta1.ReasonDeleteFailed, err.Error(),
+				errors.Is(resultErr, reconcile.TerminalError(nil)))
+			if err := r.Status().Update(ctx, team); err != nil {
+				logger.Error(err, "unable to update JSMTeam status after failed deletion")
+				return ctrl.Result{}, err
+			}
+			return result, resultErr
+		}
+		recordEvent(r.Recorder, team, corev1.EventTypeNormal, jsmv1beta1.EventDeleted, opDeleteOpsgenieTeam, id,
+			"Deleted Opsgenie team %s", id)
+		logger.Info("deleted Opsgenie team", "id", id)
+	} else if id != "" {
+		logger.Info("leaving Opsgenie team behind", "id", id, "deletionPolicy", policy)
+	}
+
+	controllerutil.RemoveFinalizer(team, jsmv1beta1.TeamFinalizer)
+	if err := r.Update(ctx, team); err != nil {
+		logger.Error(err, "unable to remove finalizer")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// teamDeletionPolicy returns the deletion policy of a managed team. Teams are
+// shared by services and people, so they are orphaned unless the spec says
+// otherwise.
+func teamDeletionPolicy(team *jsmv1beta1.JSMTeam) jsmv1beta1.DeletionPolicy {
+	if team.Spec.DeletionPolicy != "" {
+		return team.Spec.DeletionPolicy
+	}
+	return jsmv1beta1.DeletionPolicyOrphan
+}
+
+// opsgenieTeamName returns the name of the Opsgenie team, which defaults to
+// the name of the resource.
+func opsgenieTeamName(team *jsmv1beta1.JSMTeam) string {
+	if team.Spec.Name != "" {
+		return team.Spec.Name
+	}
+	return team.Name
+}
+
+// desiredTeam returns the Opsgenie team the spec asks for. Without members in
+// the spec the members are left nil, so they aren't managed.
+func desiredTeam(team *jsmv1beta1.JSMTeam) *jsmclient.OpsgenieTeamRequest {
+	var members []jsmclient.TeamMember
+	for _, m := range team.Spec.Members {
+		role := jsmclient.TeamMemberRoleUser
+		if m.Role == jsmv1beta1.TeamMemberRoleAdmin {
+			role = jsmclient.TeamMemberRoleAdmin
+		}
+		members = append(members, jsmclient.TeamMember{Username: m.Username, Role: role})
+	}
+	return &jsmclient.OpsgenieTeamRequest{
+		Name:        opsgenieTeamName(team),
+		Description: team.Spec.Description,
+		Members:     members,
+	}
+}
+
+// teamDrift returns the names of the fields in which the Opsgenie team
+// differs from the desired one. Usernames are compared ignoring case, like
+// Atlassian treats email addresses, and only if the members are managed.
+func teamDrift(desired *jsmclient.OpsgenieTeamRequest, remote *jsmclient.OpsgenieTeam) []string {
+	var drifted []string
+	if remote.Name != desired.Name {
+		drifted = append(drifted, "name")
+	}
+	if remote.Description != desired.Description {
+		drifted = append(drifted, "description")
+	}
+	if desired.Members != nil && !sameSet(memberKeys(remote.Members), memberKeys(desired.Members)) {
+		drifted = append(drifted, "members")
+	}
+	return drifted
+}
+
+// memberKeys returns a comparable "username=role" key per member.
+func memberKeys(members []jsmclient.TeamMember) []string {
+	keys := make([]string, 0, len(members))
+	for _, m := range members {
+		role := m.Role
+		if role == "" {
+			role = jsmclient.TeamMemberRoleUser
+		}
+		keys = append(keys, strings.ToLower(m.Username)+"="+string(role))
+	}
+	return keys
+}
+
+// describeMembers lists the members of a planned team.
+func describeMembers(members []jsmclient.TeamMember) string {
+	if len(members) == 0 {
+		return "no members"
+	}
+	described := make([]string, 0, len(members))
+	for _, m := range members {
+		described = append(described, fmt.Sprintf("%s (%s)", m.Username, m.Role))
+	}
+	return "members " + strings.Join(described, ", ")
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	jsmv1beta1 "github.com/artemlive/jsm-operator/api/v1beta1"
+	"github.com/go-logr/logr"
+)
+
+// teamRefs returns the JSMTeams referenced by the service with namespace and
+// role defaulted. The deprecated teamRef comes first as an owner. A team that
+// is referenced twice is listed once, as owner if either reference says so.
+func teamRefs(service *jsmv1beta1.JSMService) []jsmv1beta1.JSMTeamRef {
+	refs := service.Spec.TeamRefs
+	if service.Spec.TeamRef != nil && service.Spec.TeamRef.Name != "" {
+		refs = append([]jsmv1beta1.JSMTeamRef{*service.Spec.TeamRef}, refs...)
+	}
+
+	var resolved []jsmv1beta1.JSMTeamRef
+	for _, ref := range refs {
+		if ref.Name == "" {
+			continue
+		}
+		if ref.Namespace == "" {
+			ref.Namespace = service.Namespace
+		}
+		if ref.Role == "" {
+			ref.Role = jsmv1beta1.TeamRoleOwner
+		}
+		i := slices.IndexFunc(resolved, func(r jsmv1beta1.JSMTeamRef) bool {
+			return r.Name == ref.Name && r.Namespace == ref.Namespace
+		})
+		if i < 0 {
+			resolved = append(resolved, ref)
+		} else if ref.Role == jsmv1beta1.TeamRoleOwner {
+			resolved[i].Role = ref.Role
+		}
+	}
+	return resolved
+}
+
+// resolveTeams looks up the referenced JSMTeams and their team ARIs. If a
+// team can't be used yet, the TeamResolved condition tells why and ok is
+// false; err is only set when the reconcile has to be retried or stalls. A
+// service without teams, e.g. a business service, has no responders.
+func (r *JSMServiceReconciler) resolveTeams(ctx context.Context, service *jsmv1beta1.JSMService, refs []jsmv1beta1.JSMTeamRef, log logr.Logger) (teams []jsmv1beta1.ServiceTeamStatus, ok bool, err error) {
+	if len(refs) == 0 {
+		r.setCondition(service, jsmv1beta1.ConditionTeamResolved, metav1.ConditionTrue, jsmv1beta1.ReasonNoTeamRef,
+			"No team referenced, the service has no responders")
+		return nil, true, nil
+	}
+
+	for _, ref := range refs {
+		key := types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}
+		var team jsmv1beta1.JSMTeam
+		if err := r.Get(ctx, key, &team); err != nil {
+			// the JSMTeam watch brings the service back once the team exists
+			if apierrors.IsNotFound(err) {
+				log.Info("Referenced JSMTeam not found, waiting for it", "team", key)
+				r.setCondition(service, jsmv1beta1.ConditionTeamResolved, metav1.ConditionFalse, jsmv1beta1.ReasonTeamNotFound,
+					fmt.Sprintf("JSMTeam %q not found", teamName(service, key)))
+				return nil, false, nil
+			}
+			log.Error(err, "Failed to get referenced JSMTeam", "team", key)
+			return nil, false, err
+		}
+
+		if !teamAllows(&team, service.Namespace) {
+			err := fmt.Errorf("JSMTeam %q doesn't allow references from namespace %q", teamName(service, key), service.Namespace)
+			log.Error(err, "Referenced JSMTeam is not allowed", "team", key)
+			r.setCondition(service, jsmv1beta1.ConditionTeamResolved, metav1.ConditionFalse, jsmv1beta1.ReasonTeamRefNotAllowed, err.Error())
+			return nil, false, reconcile.TerminalError(err)
+		}
+
+		if team.Status.ID == "" {
+			log.Info("Referenced JSMTeam has no ID yet, waiting for it", "team", key)
+			r.setCondition(service, jsmv1beta1.ConditionTeamResolved, metav1.ConditionFalse, jsmv1beta1.ReasonTeamNotReady,
+				fmt.Sprintf("JSMTeam %q has no team ID yet", teamName(service, key)))
+			return nil, false, nil
+		}
+
+		teams = append(teams, jsmv1beta1.ServiceTeamStatus{
+			Name:      ref.Name,
+			Namespace: ref.Namespace,
+			Role:      ref.Role,
+			ID:        team.Status.ID,
+		})
+	}
+
+	resolved := make([]string, 0, len(teams))
+	for _, team := range teams {
+		key := types.NamespacedName{Name: team.Name, Namespace: team.Namespace}
+		resolved = append(resolved, fmt.Sprintf("JSMTeam %q resolved to %s", teamName(service, key), team.ID))
+	}
+	r.setCondition(service, jsmv1beta1.ConditionTeamResolved, metav1.ConditionTrue, jsmv1beta1.ReasonResolved, strings.Join(resolved, ", "))
+	return teams, true, nil
+}
+
+// teamName is how a referenced team is shown in messages: by name in the
+// namespace of the service, otherwise with its namespace.
+func teamName(service *jsmv1beta1.JSMService, key types.NamespacedName) string {
+	if key.Namespace == service.Namespace {
+		return key.Name
+	}
+	return key.String()
+}
+
+// teamAllows reports whether services in namespace may reference the team.
+func teamAllows(team *jsmv1beta1.JSMTeam, namespace string) bool {
+	return team.Namespace == namespace ||
+		slices.Contains(team.Spec.AllowedNamespaces, namespace) ||
+		slices.Contains(team.Spec.AllowedNamespaces, "*")
+}
+
+// responderIDs returns the ARIs of all referenced teams.
+func responderIDs(teams []jsmv1beta1.ServiceTeamStatus) []string {
+	ids := make([]string, 0, len(teams))
+	for _, team := range teams {
+		if !slices.Contains(ids, team.ID) {
+			ids = append(ids, team.ID)
+		}
+	}
+	return ids
+}
+
+// ownerIDs returns the ARIs of the owner teams, which are linked with the
+// service by an Opsgenie team relationship.
+func ownerIDs(teams []jsmv1beta1.ServiceTeamStatus) []string {
+	ids := make([]string, 0, len(teams))
+	for _, team := range teams {
+		if team.Role == jsmv1beta1.TeamRoleOwner && !slices.Contains(ids, team.ID) {
+			ids = append(ids, team.ID)
+		}
+	}
+	return ids
+}
+
+// teamsApplied reports whether the resolved teams were already applied to the
+// JSM service, i.e. neither a reference nor a team ARI changed since.
+func teamsApplied(service *jsmv1beta1.JSMService, teams []jsmv1beta1.ServiceTeamStatus) bool {
+	return slices.EqualFunc(service.Status.Teams, teams, func(applied, resolved jsmv1beta1.ServiceTeamStatus) bool {
+		return applied.Name == resolved.Name && applied.Namespace == resolved.Namespace &&
+			applied.Role == resolved.Role && applied.ID == resolved.ID
+	})
+}
+
+// teamRefIndex indexes JSMServices by the namespaced names of the JSMTeams
+// they reference.
+const teamRefIndex = "spec.teamRefs"
+
+// indexTeamRefs is the indexer function of teamRefIndex.
+func indexTeamRefs(obj client.Object) []string {
+	service, ok := obj.(*jsmv1beta1.JSMService)
+	if !ok {
+		return nil
+	}
+	var keys []string
+	for _, ref := range teamRefs(service) {
+		keys = append(keys, types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}.String())
+	}
+	return keys
+}
+
+// servicesForTeam maps a JSMTeam to the JSMServices referencing it, so they
+// are reconciled once the team resolves or changes.
+func (r *JSMServiceReconciler) servicesForTeam(ctx context.Context, obj client.Object) []reconcile.Request {
+	key := client.ObjectKeyFromObject(obj).String()
+	var services jsmv1beta1.JSMServiceList
+	if err := r.List(ctx, &services, client.MatchingFields{teamRefIndex: key}); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list JSMServices referencing JSMTeam", "team", key)
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(services.Items))
+	for _, service := range services.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&service)})
+	}
+	return requests
+}
+
+// teamChanged passes the JSMTeam events that matter to the services
+// referencing it: creation, deletion, a spec change such as the allowed
+// namespaces and a change of the resolved team ID.
+var teamChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldTeam, ok := e.ObjectOld.(*jsmv1beta1.JSMTeam)
+		if !ok {
+			return false
+		}
+		newTeam, ok := e.ObjectNew.(*jsmv1beta1.JSMTeam)
+		return ok && (oldTeam.Generation != newTeam.Generation || oldTeam.Status.ID != newTeam.Status.ID)
+	},
+	GenericFunc: func(event.GenericEvent) bool { return false },
+}